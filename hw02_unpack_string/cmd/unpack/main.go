@@ -0,0 +1,25 @@
+package main
+
+import "os"
+
+// коды завершения.
+const (
+	exitOK      = 0
+	exitInvalid = 1 // во входе есть некорректные строки
+	exitUsage   = 2 // ошибка параметров или ввода-вывода
+)
+
+const usage = `Usage: unpack <command> [flags] [files...]
+
+Commands:
+  unpack  распаковать строки
+  pack    упаковать строки
+  check   проверить строки. для некорректных выводятся строка и колонка ошибки
+
+Если файлы не указаны - строки читаются из stdin.
+Флаги команды: unpack <command> -h
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	hw02unpackstring "github.com/fixme_my_friend/hw02_unpack_string"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+// имя источника для stdin в сообщениях.
+const stdinName = "stdin"
+
+// форматы вывода.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// мапинг названий политик на значения для флагов.
+var (
+	digitPolicies = map[string]hw02unpackstring.DigitPolicy{
+		"ascii":   hw02unpackstring.DigitsASCII,
+		"unicode": hw02unpackstring.DigitsUnicode,
+		"reject":  hw02unpackstring.DigitsReject,
+	}
+	combiningPolicies = map[string]hw02unpackstring.CombiningPolicy{
+		"separate": hw02unpackstring.CombiningSeparate,
+		"cluster":  hw02unpackstring.CombiningCluster,
+	}
+)
+
+// config - параметры запуска команды.
+type config struct {
+	command   string
+	format    string
+	opts      hw02unpackstring.StreamOptions
+	digits    string
+	combining string
+	files     []string
+}
+
+// record - результат обработки одной строки. в формате json выводится как есть, по строке на запись.
+type record struct {
+	File   string  `json:"file"`
+	Line   int     `json:"line"`
+	Column int     `json:"column,omitempty"`
+	Output *string `json:"output,omitempty"` // результат, в том числе пустой. nil - ошибка или check
+	Error  string  `json:"error,omitempty"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+// run выполняет команду и возвращает код завершения.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfg, err := parseArgs(args, stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stderr, "Error: ", err)
+		}
+		return exitUsage
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	invalid := false
+	process := func(name string, r io.Reader) error {
+		bad, err := processLines(cfg, name, r, out, stderr)
+		invalid = invalid || bad
+		return err
+	}
+
+	if len(cfg.files) == 0 {
+		err = process(stdinName, stdin)
+	}
+	for _, name := range cfg.files {
+		if err = processFile(name, process); err != nil {
+			break
+		}
+	}
+
+	switch {
+	case err != nil:
+		out.Flush()
+		fmt.Fprintln(stderr, "Error: ", err)
+		return exitUsage
+	case invalid:
+		return exitInvalid
+	default:
+		return exitOK
+	}
+}
+
+func processFile(name string, process func(name string, r io.Reader) error) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return process(name, f)
+}
+
+func parseArgs(args []string, stderr io.Writer) (*config, error) {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return nil, flag.ErrHelp
+	}
+
+	cfg := &config{command: args[0]}
+	switch cfg.command {
+	case "unpack", "pack", "check":
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stderr, usage)
+		return nil, flag.ErrHelp
+	default:
+		return nil, fmt.Errorf("%w : %q", ErrUnknownCommand, cfg.command)
+	}
+
+	fs := flag.NewFlagSet(cfg.command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.format, "format", formatText, "output format: text or json")
+	fs.BoolVar(&cfg.opts.MultiDigit, "multi-digit", false, "allow repeat counts of several digits")
+	fs.IntVar(&cfg.opts.MaxRepeat, "max-repeat", 0, "max repeat count, 0 - 10000 with -multi-digit")
+	fs.StringVar(&cfg.digits, "digits", "ascii", "non-ASCII digits policy: ascii, unicode or reject")
+	fs.StringVar(&cfg.combining, "combining", "separate", "combining characters policy: separate or cluster")
+	fs.Int64Var(&cfg.opts.MaxOutputSize, "max-output", 0, "max unpacked line size in bytes, 0 - unlimited")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	cfg.files = fs.Args()
+
+	var ok bool
+	if cfg.format != formatText && cfg.format != formatJSON {
+		return nil, fmt.Errorf("unknown output format %q", cfg.format)
+	}
+	if cfg.opts.Digits, ok = digitPolicies[cfg.digits]; !ok {
+		return nil, fmt.Errorf("unknown digits policy %q", cfg.digits)
+	}
+	if cfg.opts.Combining, ok = combiningPolicies[cfg.combining]; !ok {
+		return nil, fmt.Errorf("unknown combining policy %q", cfg.combining)
+	}
+
+	return cfg, nil
+}
+
+// обрабатываем строки источника. invalid = true - встретились некорректные строки.
+func processLines(cfg *config, name string, r io.Reader, out *bufio.Writer, stderr io.Writer) (bool, error) {
+	scanner := bufio.NewScanner(r)
+	// строки могут быть длинными - увеличиваем максимальный размер буфера
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 64*1024*1024)
+
+	invalid := false
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		rec := processLine(cfg, scanner.Text())
+		rec.File, rec.Line = name, lineNum
+		if rec.Error != "" {
+			invalid = true
+		}
+
+		if err := writeRecord(cfg, rec, out, stderr); err != nil {
+			return invalid, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return invalid, fmt.Errorf("read %s: %w", name, err)
+	}
+	return invalid, nil
+}
+
+func processLine(cfg *config, line string) record {
+	var (
+		rec    record
+		output string
+		err    error
+	)
+
+	switch cfg.command {
+	case "pack":
+		output, err = hw02unpackstring.Pack(line)
+	default:
+		sBldr := strings.Builder{}
+		err = hw02unpackstring.UnpackStream(strings.NewReader(line), &sBldr, cfg.opts)
+		output = sBldr.String()
+	}
+
+	if err != nil {
+		rec.Error = err.Error()
+
+		var unpackErr *hw02unpackstring.UnpackError
+		if errors.As(err, &unpackErr) {
+			rec.Column = unpackErr.RuneOffset + 1
+			rec.Reason = unpackErr.Reason.String()
+		}
+	}
+
+	// check выводит только ошибки
+	if err == nil && cfg.command != "check" {
+		rec.Output = &output
+	}
+
+	return rec
+}
+
+// выводим результат. в текстовом формате ошибки пишутся в stderr (для check - в stdout),
+// результат - в stdout. в формате json все пишется в stdout.
+func writeRecord(cfg *config, rec record, out *bufio.Writer, stderr io.Writer) error {
+	if cfg.command == "check" && rec.Error == "" {
+		return nil
+	}
+
+	if cfg.format == formatJSON {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	}
+
+	if rec.Error == "" {
+		_, err := fmt.Fprintln(out, *rec.Output)
+		return err
+	}
+
+	if cfg.command == "check" {
+		_, err := fmt.Fprintf(out, "%s:%d:%d: %s\n", rec.File, rec.Line, rec.Column, rec.Error)
+		return err
+	}
+
+	// stderr не буферизуется: сначала выводим накопленные результаты, чтобы сохранить порядок строк
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(stderr, "%s:%d:%d: %s\n", rec.File, rec.Line, rec.Column, rec.Error)
+	return err
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func runCmd(t *testing.T, input string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	outBuf, errBuf := bytes.Buffer{}, bytes.Buffer{}
+	code = run(args, strings.NewReader(input), &outBuf, &errBuf)
+	return code, outBuf.String(), errBuf.String()
+}
+
+func TestRun(t *testing.T) {
+	input := "a4bc2d5e\n3abc\nqw\\ne\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		code     int
+		expected string
+	}{
+		{
+			name: "unpack", args: []string{"unpack"}, input: "a4bc2d5e\nabc\n", code: exitOK,
+			expected: "aaaabccddddde\nabc\n",
+		},
+		{
+			name: "pack", args: []string{"pack"}, input: "aaaabccddddde\nqwe45\n", code: exitOK,
+			expected: "a4bc2d5e\nqwe\\4\\5\n",
+		},
+		{name: "unpack invalid", args: []string{"unpack"}, input: input, code: exitInvalid, expected: "aaaabccddddde\n"},
+		{
+			name: "unpack options", args: []string{"unpack", "-multi-digit"}, input: "a12\n", code: exitOK,
+			expected: "aaaaaaaaaaaa\n",
+		},
+		{name: "check valid", args: []string{"check"}, input: "a4bc2d5e\nabc\n", code: exitOK, expected: ""},
+		{
+			name: "check text", args: []string{"check"}, input: input, code: exitInvalid,
+			expected: "stdin:2:1: start from number '3' at rune 0 (byte 0): invalid string\n" +
+				"stdin:3:4: invalid escaped symbol 'n' at rune 3 (byte 3): invalid escape sequence: invalid string\n",
+		},
+		{
+			name: "check json", args: []string{"check", "-format", "json"}, input: "ab\naa10\n", code: exitInvalid,
+			expected: `{"file":"stdin","line":2,"column":4,` +
+				`"error":"two numbers in a row '0' at rune 3 (byte 3): invalid string","reason":"two numbers in a row"}` + "\n",
+		},
+		{
+			name: "unpack json", args: []string{"unpack", "-format=json"}, input: "a2\n", code: exitOK,
+			expected: `{"file":"stdin","line":1,"output":"aa"}` + "\n",
+		},
+		{
+			name: "empty output json", args: []string{"unpack", "-format=json"}, input: "a0\n3\n",
+			code: exitInvalid,
+			expected: `{"file":"stdin","line":1,"output":""}` + "\n" +
+				`{"file":"stdin","line":2,"column":1,` +
+				`"error":"start from number '3' at rune 0 (byte 0): invalid string",` +
+				`"reason":"start from number"}` + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			code, stdout, _ := runCmd(t, tc.input, tc.args...)
+			require.Equal(t, tc.code, code)
+			require.Equal(t, tc.expected, stdout)
+		})
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.txt")
+	second := filepath.Join(dir, "second.txt")
+	require.NoError(t, os.WriteFile(first, []byte("a2\nb3\n"), 0o600))
+	require.NoError(t, os.WriteFile(second, []byte("c\n45\n"), 0o600))
+
+	code, stdout, stderr := runCmd(t, "", "unpack", first, second)
+	require.Equal(t, exitInvalid, code)
+	require.Equal(t, "aa\nbbb\nc\n", stdout)
+	require.Equal(t, second+":2:1: start from number '4' at rune 0 (byte 0): invalid string\n", stderr)
+
+	code, _, stderr = runCmd(t, "", "check", filepath.Join(dir, "missing.txt"))
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "missing.txt")
+}
+
+func TestRunOrder(t *testing.T) {
+	// результаты и ошибки в общем выводе идут в порядке строк входа
+	buf := bytes.Buffer{}
+	code := run([]string{"unpack"}, strings.NewReader("a2\n3\nb2\n"), &buf, &buf)
+	require.Equal(t, exitInvalid, code)
+	require.Equal(t, "aa\nstdin:2:1: start from number '3' at rune 0 (byte 0): invalid string\nbb\n", buf.String())
+}
+
+func TestRunUsage(t *testing.T) {
+	invalidArgs := [][]string{
+		{},
+		{"unknown"},
+		{"unpack", "-format", "xml"},
+		{"unpack", "-digits", "roman"},
+		{"unpack", "-combining", "none"},
+		{"unpack", "-no-such-flag"},
+	}
+	for _, args := range invalidArgs {
+		code, _, stderr := runCmd(t, "", args...)
+		require.Equal(t, exitUsage, code, "args %v", args)
+		require.NotEmpty(t, stderr, "args %v", args)
+	}
+}
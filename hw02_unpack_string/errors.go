@@ -0,0 +1,57 @@
+package hw02unpackstring
+
+import "fmt"
+
+// причины ошибки распаковки.
+type Reason uint
+
+const (
+	ReasonUnknown Reason = iota
+	ReasonStartFromNumber
+	ReasonTwoNumbersInRow
+	ReasonInvalidEscape
+	ReasonEscapeAtEnd
+	ReasonRepeatTooLarge
+	ReasonUnicodeDigit
+	ReasonTooManyCombining
+)
+
+// мапинг причин на текст ошибки.
+var reasonNames = map[Reason]string{
+	ReasonUnknown:          "unknown reason",
+	ReasonStartFromNumber:  "start from number",
+	ReasonTwoNumbersInRow:  "two numbers in a row",
+	ReasonInvalidEscape:    "invalid escaped symbol",
+	ReasonEscapeAtEnd:      "escape at the end of string",
+	ReasonRepeatTooLarge:   "repeat count too large",
+	ReasonUnicodeDigit:     "non-ASCII digit",
+	ReasonTooManyCombining: "too many combining marks",
+}
+
+func (r Reason) String() string {
+	if name, ok := reasonNames[r]; ok {
+		return name
+	}
+	return reasonNames[ReasonUnknown]
+}
+
+// UnpackError - ошибка распаковки с указанием места в исходной строке.
+// Смещения считаются от нуля. Для ReasonEscapeAtEnd Rune - сам символ экранирования.
+// errors.Is(err, ErrInvalidString) для неё возвращает true, для ошибок экранирования - и ErrInvalidEscape.
+type UnpackError struct {
+	RuneOffset int
+	ByteOffset int
+	Rune       rune
+	Reason     Reason
+}
+
+func (e *UnpackError) Error() string {
+	return fmt.Sprintf("%s %q at rune %d (byte %d): %s", e.Reason, e.Rune, e.RuneOffset, e.ByteOffset, e.Unwrap())
+}
+
+func (e *UnpackError) Unwrap() error {
+	if e.Reason == ReasonInvalidEscape || e.Reason == ReasonEscapeAtEnd {
+		return ErrInvalidEscape
+	}
+	return ErrInvalidString
+}
@@ -0,0 +1,118 @@
+package hw02unpackstring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidOptions = errors.New("invalid options")
+
+// политика обработки не-ASCII цифр (unicode.IsDigit).
+type DigitPolicy uint
+
+const (
+	// DigitsASCII - количество повторов задается только цифрами 0-9, прочие цифры - обычные символы.
+	DigitsASCII DigitPolicy = iota
+	// DigitsUnicode - количество повторов задается любой цифрой: "a٣" => "aaa".
+	DigitsUnicode
+	// DigitsReject - не-ASCII цифры во входе считаются ошибкой.
+	DigitsReject
+)
+
+// политика обработки комбинируемых символов (unicode.M).
+type CombiningPolicy uint
+
+const (
+	// CombiningSeparate - каждая руна - отдельный символ. повторяется последняя руна перед цифрой.
+	CombiningSeparate CombiningPolicy = iota
+	// CombiningCluster - комбинируемые руны присоединяются к предыдущему символу и повторяются вместе с ним:
+	// "é3" => "ééé". к символу присоединяется не больше maxCombiningMarks рун.
+	CombiningCluster
+)
+
+// максимальное количество комбинируемых рун в символе (CombiningCluster), как в Stream-Safe Text Format
+// (Unicode UAX #15). ограничивает память на символ при чтении недоверенного входа.
+const maxCombiningMarks = 30
+
+// DefaultMaxRepeat - максимальное количество повторов при MultiDigit, если MaxRepeat не задан.
+// без ограничения строка вида "a999999999999999999" потребовала бы около 10^18 байт памяти.
+const DefaultMaxRepeat = 10000
+
+// Options - параметры распаковки. нулевое значение соответствует поведению Unpack.
+type Options struct {
+	// разрешить количество повторов из нескольких цифр: "a12" => 12 символов "a".
+	MultiDigit bool
+	// максимальное количество повторов. 0 - DefaultMaxRepeat при MultiDigit (одна цифра - не больше 9).
+	// снять ограничение можно значением math.MaxInt, но для недоверенного входа тогда нужен
+	// MaxOutputSize потоковой распаковки (UnpackStream).
+	MaxRepeat int
+	// политика обработки не-ASCII цифр.
+	Digits DigitPolicy
+	// политика обработки комбинируемых символов.
+	Combining CombiningPolicy
+}
+
+func (o Options) validate() error {
+	switch {
+	case o.MaxRepeat < 0:
+		return fmt.Errorf("%w : negative max repeat %d", ErrInvalidOptions, o.MaxRepeat)
+	case o.Digits > DigitsReject:
+		return fmt.Errorf("%w : unknown digit policy %d", ErrInvalidOptions, o.Digits)
+	case o.Combining > CombiningCluster:
+		return fmt.Errorf("%w : unknown combining policy %d", ErrInvalidOptions, o.Combining)
+	}
+	return nil
+}
+
+// UnpackWithOptions - распаковка строки с настраиваемой грамматикой.
+func UnpackWithOptions(s string, opts Options) (string, error) {
+	sBldr := strings.Builder{}
+
+	if err := UnpackStream(strings.NewReader(s), &sBldr, StreamOptions{Options: opts}); err != nil {
+		return "", err
+	}
+
+	return sBldr.String(), nil
+}
+
+// максимальное количество повторов с учетом значения по умолчанию. 0 - без ограничений.
+func (o Options) maxRepeat() int {
+	if o.MaxRepeat == 0 && o.MultiDigit {
+		return DefaultMaxRepeat
+	}
+	return o.MaxRepeat
+}
+
+// значение цифры с учетом политики. ok = false - руна не задает количество повторов.
+func (o Options) digitValue(r rune) (value int, ok bool) {
+	if isNumber(r) {
+		return int(r - '0'), true
+	}
+	if o.Digits == DigitsUnicode && unicode.IsDigit(r) {
+		return unicodeDigitValue(r), true
+	}
+	return 0, false
+}
+
+// руна - комбинируемый символ, который присоединяется к предыдущему.
+func (o Options) isCombining(r rune) bool {
+	return o.Combining == CombiningCluster && unicode.Is(unicode.M, r)
+}
+
+// значение unicode-цифры. в таблице unicode.Nd цифры идут непрерывными блоками
+// от 0 до 9, поэтому значение - остаток от деления смещения в диапазоне на 10.
+func unicodeDigitValue(r rune) int {
+	for _, rng := range unicode.Nd.R16 {
+		if rune(rng.Lo) <= r && r <= rune(rng.Hi) {
+			return int(r-rune(rng.Lo)) % 10
+		}
+	}
+	for _, rng := range unicode.Nd.R32 {
+		if rune(rng.Lo) <= r && r <= rune(rng.Hi) {
+			return int(r-rune(rng.Lo)) % 10
+		}
+	}
+	return 0
+}
@@ -0,0 +1,147 @@
+package hw02unpackstring
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackWithOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     Options
+		expected string
+	}{
+		{name: "default options", input: "a4bc2d5e", opts: Options{}, expected: "aaaabccddddde"},
+		{name: "multi digit", input: "a12b", opts: Options{MultiDigit: true}, expected: strings.Repeat("a", 12) + "b"},
+		{name: "multi digit zero", input: "a00b10", opts: Options{MultiDigit: true}, expected: strings.Repeat("b", 10)},
+		{
+			name: "multi digit escaped", input: `\112\2`,
+			opts: Options{MultiDigit: true}, expected: strings.Repeat("1", 12) + "2",
+		},
+		{
+			name: "multi digit max", input: "a20",
+			opts: Options{MultiDigit: true, MaxRepeat: 20}, expected: strings.Repeat("a", 20),
+		},
+		{
+			name: "default max repeat", input: "a10000",
+			opts: Options{MultiDigit: true}, expected: strings.Repeat("a", DefaultMaxRepeat),
+		},
+		{name: "ascii digits policy", input: "a٣", opts: Options{}, expected: "a٣"},
+		{name: "unicode digits", input: "a٣b१", opts: Options{Digits: DigitsUnicode}, expected: "aaab"},
+		{
+			name: "unicode digits multi", input: "a١٢",
+			opts: Options{Digits: DigitsUnicode, MultiDigit: true}, expected: strings.Repeat("a", 12),
+		},
+		{name: "unicode digit escaped", input: `\٣2`, opts: Options{Digits: DigitsUnicode}, expected: "٣٣"},
+		{name: "combining separate", input: "e\u03013", opts: Options{}, expected: "e\u0301\u0301\u0301"},
+		{
+			name: "combining cluster", input: "e\u03013",
+			opts: Options{Combining: CombiningCluster}, expected: "e\u0301e\u0301e\u0301",
+		},
+		{
+			name: "combining cluster several marks", input: "a\u0328\u03012b0",
+			opts: Options{Combining: CombiningCluster}, expected: "a\u0328\u0301a\u0328\u0301",
+		},
+		{
+			name: "combining escaped", input: `\3` + "\u03012",
+			opts: Options{Combining: CombiningCluster}, expected: "3\u03013\u0301",
+		},
+		{
+			name: "combining at start", input: "\u03012a",
+			opts: Options{Combining: CombiningCluster}, expected: "\u0301\u0301a",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := UnpackWithOptions(tc.input, tc.opts)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestUnpackWithOptionsInvalidString(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		opts   Options
+		reason Reason
+		offset int
+	}{
+		{name: "two numbers without multi digit", input: "a12", opts: Options{}, reason: ReasonTwoNumbersInRow, offset: 2},
+		{name: "start from number", input: "12a", opts: Options{MultiDigit: true}, reason: ReasonStartFromNumber, offset: 0},
+		{
+			name: "max repeat", input: "a21",
+			opts: Options{MultiDigit: true, MaxRepeat: 20}, reason: ReasonRepeatTooLarge, offset: 2,
+		},
+		{name: "max repeat single digit", input: "ab9", opts: Options{MaxRepeat: 5}, reason: ReasonRepeatTooLarge, offset: 2},
+		{
+			name: "repeat overflow", input: "a" + strings.Repeat("9", 30),
+			opts: Options{MultiDigit: true, MaxRepeat: math.MaxInt}, reason: ReasonRepeatTooLarge, offset: 19,
+		},
+		{
+			name: "default max repeat", input: "a999999999999999999", opts: Options{MultiDigit: true},
+			reason: ReasonRepeatTooLarge, offset: 5,
+		},
+		{
+			name: "reject unicode digit", input: "ab٣",
+			opts: Options{Digits: DigitsReject}, reason: ReasonUnicodeDigit, offset: 2,
+		},
+		{name: "escaped unicode digit", input: `a\٣`, opts: Options{}, reason: ReasonInvalidEscape, offset: 2},
+		{
+			name: "two unicode digits", input: "a٣٣",
+			opts: Options{Digits: DigitsUnicode}, reason: ReasonTwoNumbersInRow, offset: 2,
+		},
+		{
+			name: "unicode start from number", input: "٣a",
+			opts: Options{Digits: DigitsUnicode}, reason: ReasonStartFromNumber, offset: 0,
+		},
+		{
+			name: "too many combining marks", input: "a" + strings.Repeat("\u0301", 1<<20),
+			opts: Options{Combining: CombiningCluster}, reason: ReasonTooManyCombining, offset: 31,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnpackWithOptions(tc.input, tc.opts)
+			require.Truef(t, errors.Is(err, ErrInvalidString), "actual error %q", err)
+
+			var unpackErr *UnpackError
+			require.ErrorAs(t, err, &unpackErr)
+			require.Equal(t, tc.reason, unpackErr.Reason)
+			require.Equal(t, tc.offset, unpackErr.RuneOffset)
+		})
+	}
+}
+
+func TestUnpackWithInvalidOptions(t *testing.T) {
+	invalidOptions := []Options{
+		{MaxRepeat: -1},
+		{Digits: DigitsReject + 1},
+		{Combining: CombiningCluster + 1},
+	}
+	for _, opts := range invalidOptions {
+		_, err := UnpackWithOptions("a2", opts)
+		require.ErrorIs(t, err, ErrInvalidOptions)
+	}
+}
+
+func TestUnicodeDigitValue(t *testing.T) {
+	// значение каждой unicode-цифры должно совпадать со значением, полученным по её позиции в блоке
+	for _, rngs := range [][2]rune{{'0', '9'}, {'٠', '٩'}, {'०', '९'}, {'０', '９'}, {0x1D7CE, 0x1D7FF}} {
+		for r := rngs[0]; r <= rngs[1]; r++ {
+			require.True(t, unicode.IsDigit(r))
+			require.Equal(t, int(r-rngs[0])%10, unicodeDigitValue(r), "digit %q", r)
+		}
+	}
+}
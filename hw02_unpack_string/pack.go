@@ -0,0 +1,59 @@
+package hw02unpackstring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// максимальное количество повторов, которое можно записать одной цифрой.
+const maxRepeatCnt = 9
+
+// Pack - обратная к Unpack функция. Сворачивает серии одинаковых символов в формат "символ+цифра".
+// Серии длиннее 9 символов разбиваются на несколько частей: "a"*12 => "a9a3".
+// Цифры и слэши экранируются, поэтому упаковать можно любую корректную utf-8 строку.
+func Pack(s string) (string, error) {
+	sBldr := strings.Builder{}
+	rArr := []rune(s)
+
+	// некорректные utf-8 последовательности при переводе в руны заменяются на U+FFFD,
+	// и после распаковки исходную строку не получить
+	if !utf8.ValidString(s) {
+		return "", fmt.Errorf("not utf-8 string: %w", ErrInvalidString)
+	}
+
+	// алгоритм следующий: считаем длину серии одинаковых символов, начиная с текущего,
+	// и записываем серию частями не более maxRepeatCnt символов.
+	for i := 0; i < len(rArr); {
+		runLen := 1
+		for i+runLen < len(rArr) && rArr[i+runLen] == rArr[i] {
+			runLen++
+		}
+
+		writeRun(&sBldr, rArr[i], runLen)
+		i += runLen
+	}
+
+	return sBldr.String(), nil
+}
+
+// ф-я записи серии символов с разбивкой на части не более maxRepeatCnt.
+func writeRun(sBldr *strings.Builder, r rune, runLen int) {
+	for ; runLen > 0; runLen -= maxRepeatCnt {
+		cnt := runLen
+		if cnt > maxRepeatCnt {
+			cnt = maxRepeatCnt
+		}
+
+		// цифры и слэши экранируем, иначе Unpack воспримет их как количество повторов или экранирование
+		if isEscapable(r) {
+			sBldr.WriteRune(escapeRune)
+		}
+		sBldr.WriteRune(r)
+		// одиночный символ пишем без цифры
+		if cnt > 1 {
+			sBldr.WriteString(strconv.Itoa(cnt))
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package hw02unpackstring
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "aaaabccddddde", expected: "a4bc2d5e"},
+		{input: "abccd", expected: "abc2d"},
+		{input: "", expected: ""},
+		{input: "     ", expected: " 5"},
+		{input: "aaa\n\n\b\b\b", expected: "a3\n2\b3"},
+		{input: strings.Repeat("a", 9), expected: "a9"},
+		{input: strings.Repeat("a", 10), expected: "a9a"},
+		{input: strings.Repeat("a", 12) + "b", expected: "a9a3b"},
+		{input: strings.Repeat("a", 27), expected: "a9a9a9"},
+		{input: "日日日本本語", expected: "日3本2語"},
+		// digits and slashes are escaped
+		{input: "qwe45", expected: `qwe\4\5`},
+		{input: "qwe44444", expected: `qwe\45`},
+		{input: `qwe\\\\\`, expected: `qwe\\5`},
+		{input: "v1.10.0", expected: `v\1.\1\0.\0`},
+		{input: strings.Repeat("0", 11), expected: `\09\02`},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := Pack(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestPackInvalidString(t *testing.T) {
+	invalidStrings := []string{"\xff", "abc\xc0", "a\xe6\x97b"}
+	for _, tc := range invalidStrings {
+		tc := tc
+		t.Run(tc, func(t *testing.T) {
+			_, err := Pack(tc)
+			require.Truef(t, errors.Is(err, ErrInvalidString), "actual error %q", err)
+		})
+	}
+}
+
+// генератор строк из серий случайной длины. quick.Check на случайных строках
+// почти не даёт повторов символов, поэтому серии генерируем отдельно.
+func randomRuns(rnd *rand.Rand) string {
+	alphabet := []rune("ab \n\\日é019")
+	sBldr := strings.Builder{}
+	for i := rnd.Intn(10); i > 0; i-- {
+		sBldr.WriteString(strings.Repeat(string(alphabet[rnd.Intn(len(alphabet))]), 1+rnd.Intn(30)))
+	}
+	return sBldr.String()
+}
+
+func TestPackUnpackRoundTrip(t *testing.T) {
+	roundTrip := func(s string) bool {
+		packed, err := Pack(s)
+		if err != nil {
+			return false
+		}
+		unpacked, err := Unpack(packed)
+		return err == nil && unpacked == s
+	}
+
+	t.Run("random strings", func(t *testing.T) {
+		require.NoError(t, quick.Check(roundTrip, nil))
+	})
+
+	t.Run("random runs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 1000; i++ {
+			s := randomRuns(rnd)
+			require.Truef(t, roundTrip(s), "round trip failed for %q", s)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package hw02unpackstring
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrOutputLimitExceeded = errors.New("output size limit exceeded")
+
+// StreamOptions - параметры потоковой распаковки.
+type StreamOptions struct {
+	Options
+	// максимальный размер результата в байтах. 0 - без ограничений.
+	MaxOutputSize int64
+}
+
+// OutputLimitError - ошибка превышения максимального размера результата.
+// errors.Is(err, ErrOutputLimitExceeded) для неё возвращает true.
+type OutputLimitError struct {
+	Limit int64
+}
+
+func (e *OutputLimitError) Error() string {
+	return fmt.Sprintf("%s: limit %d bytes", ErrOutputLimitExceeded, e.Limit)
+}
+
+func (e *OutputLimitError) Unwrap() error {
+	return ErrOutputLimitExceeded
+}
+
+// UnpackStream распаковывает данные из r в w по одной руне, не загружая вход и результат в память целиком.
+// При ошибке в w может остаться частично распакованный результат.
+func UnpackStream(r io.Reader, w io.Writer, opts StreamOptions) error {
+	if opts.MaxOutputSize < 0 {
+		return fmt.Errorf("%w : negative max output size %d", ErrInvalidOptions, opts.MaxOutputSize)
+	}
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	d := decoder{opts: opts.Options, limit: opts.MaxOutputSize}
+
+	// если источник уже умеет читать по руне (strings.Reader, bufio.Reader) - буфер не нужен
+	if rs, ok := r.(io.RuneScanner); ok {
+		d.in = rs
+	} else {
+		d.in = bufio.NewReader(r)
+	}
+
+	// то же для приемника (strings.Builder, bufio.Writer)
+	if rw, ok := w.(runeWriter); ok {
+		d.out = rw
+		return d.decode()
+	}
+
+	bw := bufio.NewWriter(w)
+	d.out = bw
+	if err := d.decode(); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
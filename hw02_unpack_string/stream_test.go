@@ -0,0 +1,106 @@
+package hw02unpackstring
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// обертка, скрывающая метод WriteRune, чтобы проверить запись через буфер.
+type plainWriter struct {
+	w io.Writer
+}
+
+func (p plainWriter) Write(b []byte) (int, error) {
+	return p.w.Write(b)
+}
+
+// бесконечный источник, повторяющий заданную строку.
+type repeatReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *repeatReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = r.data[r.pos]
+		r.pos = (r.pos + 1) % len(r.data)
+	}
+	return len(b), nil
+}
+
+func TestUnpackStream(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "a4bc2d5e", expected: "aaaabccddddde"},
+		{input: "", expected: ""},
+		{input: "aaa0b", expected: "aab"},
+		{input: "日3本2語", expected: "日日日本本語"},
+		{input: `qwe\45`, expected: `qwe44444`},
+		{input: `qwe\\5`, expected: `qwe\\\\\`},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			out := bytes.Buffer{}
+			err := UnpackStream(iotest.OneByteReader(strings.NewReader(tc.input)), plainWriter{&out}, StreamOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, out.String())
+		})
+	}
+}
+
+func TestUnpackStreamInvalidString(t *testing.T) {
+	invalidStrings := []string{"3abc", "45", "aaa10b", `qw\ne`, `abc\`}
+	for _, tc := range invalidStrings {
+		tc := tc
+		t.Run(tc, func(t *testing.T) {
+			err := UnpackStream(strings.NewReader(tc), io.Discard, StreamOptions{})
+			require.Truef(t, errors.Is(err, ErrInvalidString), "actual error %q", err)
+		})
+	}
+}
+
+func TestUnpackStreamLimit(t *testing.T) {
+	t.Run("output fits the limit", func(t *testing.T) {
+		out := strings.Builder{}
+		err := UnpackStream(strings.NewReader("a4日2"), &out, StreamOptions{MaxOutputSize: 10})
+		require.NoError(t, err)
+		require.Equal(t, "aaaa日日", out.String())
+	})
+
+	t.Run("output exceeds the limit", func(t *testing.T) {
+		out := strings.Builder{}
+		err := UnpackStream(strings.NewReader("a4日2"), &out, StreamOptions{MaxOutputSize: 9})
+
+		var limitErr *OutputLimitError
+		require.ErrorAs(t, err, &limitErr)
+		require.Equal(t, int64(9), limitErr.Limit)
+		require.ErrorIs(t, err, ErrOutputLimitExceeded)
+		require.Equal(t, "aaaa", out.String())
+	})
+
+	t.Run("endless input", func(t *testing.T) {
+		err := UnpackStream(&repeatReader{data: []byte("a9b9c9")}, io.Discard, StreamOptions{MaxOutputSize: 1 << 20})
+		require.ErrorIs(t, err, ErrOutputLimitExceeded)
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		err := UnpackStream(strings.NewReader("a4"), io.Discard, StreamOptions{MaxOutputSize: -1})
+		require.Error(t, err)
+	})
+}
+
+func TestUnpackStreamReadError(t *testing.T) {
+	errRead := errors.New("read failed")
+	err := UnpackStream(iotest.ErrReader(errRead), io.Discard, StreamOptions{})
+	require.ErrorIs(t, err, errRead)
+}
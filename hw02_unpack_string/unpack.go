@@ -3,47 +3,208 @@ package hw02unpackstring
 import (
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"strconv"
-	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
-var ErrInvalidString = errors.New("invalid string")
+// символ экранирования.
+const escapeRune = '\\'
+
+var (
+	ErrInvalidString = errors.New("invalid string")
+	// ошибка экранирования. экранировать можно только цифру или слэш.
+	ErrInvalidEscape = fmt.Errorf("invalid escape sequence: %w", ErrInvalidString)
+)
 
 func Unpack(s string) (string, error) {
-	sBldr := strings.Builder{}
-	rArr := []rune(s)
-
-	if s == "" {
-		return "", nil
-	}
-	// первый символ не должен быть цифрой
-	if isNumber(rArr[0]) {
-		return "", fmt.Errorf("start from number: %w", ErrInvalidString)
-	}
-
-	// алгоритм следующий: анализируем текущий символ, печатаем предыдущий.
-	// начинаем со второго (индекс 1)
-	// если текущий - цифра и предыдущий - цифра, то - ошибка
-	// если текущий - цифра и предыдущий - не цифра. печатаем предыдущий указанное число раз
-	// если текущий - не цифра и предыдущий - цифра, то пропукаем
-	// если текущий - не цифра и предыдущий - не цифра, печатаем предыдущий
-	for i := 1; i < len(rArr); i++ {
-		if repeatCnt, err := strconv.Atoi(string(rArr[i])); err == nil {
-			if isNumber(rArr[i-1]) {
-				return "", fmt.Errorf("two numbers in a row: %w", ErrInvalidString)
+	return UnpackWithOptions(s, Options{})
+}
+
+// интерфейс вывода распакованных рун. ему удовлетворяют strings.Builder и bufio.Writer.
+type runeWriter interface {
+	WriteRune(r rune) (int, error)
+}
+
+// decoder - распаковщик, читающий вход по одной руне. память не зависит от размера входа и результата.
+type decoder struct {
+	in      io.RuneScanner
+	out     runeWriter
+	opts    Options
+	limit   int64    // максимальный размер результата в байтах. 0 - без ограничений
+	written int64    // размер уже записанного результата в байтах
+	next    position // позиция следующей руны во входе
+	last    position // позиция последней прочитанной руны
+	symbol  []rune   // текущий символ: руна и присоединенные к ней комбинируемые руны
+}
+
+// позиция руны во входе: номер руны и смещение в байтах.
+type position struct {
+	runeOff int
+	byteOff int
+}
+
+// алгоритм следующий: читаем очередной символ (с учетом экранирования) и смотрим на следующий за ним.
+// если следующий - цифра, печатаем символ указанное число раз (цифру пропускаем), иначе - печатаем один раз.
+// цифра на месте символа - ошибка: либо строка начинается с цифры, либо две цифры подряд.
+func (d *decoder) decode() error {
+	for {
+		r, ok, err := d.readRune()
+		if err != nil || !ok {
+			return err
+		}
+
+		_, isDigit := d.opts.digitValue(r)
+		switch {
+		case isDigit && d.last.runeOff == 0:
+			return d.errorAt(ReasonStartFromNumber, r)
+		case isDigit:
+			return d.errorAt(ReasonTwoNumbersInRow, r)
+		case r == escapeRune:
+			// экранированный символ печатаем как обычный
+			if r, ok, err = d.readRune(); err != nil {
+				return err
 			}
-			sBldr.WriteString(strings.Repeat(string(rArr[i-1]), repeatCnt))
-		} else if !isNumber(rArr[i-1]) {
-			sBldr.WriteRune(rArr[i-1])
+			if !ok {
+				return d.errorAt(ReasonEscapeAtEnd, escapeRune)
+			}
+			if _, isDigit = d.opts.digitValue(r); !isDigit && r != escapeRune {
+				return d.errorAt(ReasonInvalidEscape, r)
+			}
+		}
+
+		if err := d.readSymbol(r); err != nil {
+			return err
 		}
+		repeatCnt, err := d.readCount()
+		if err != nil {
+			return err
+		}
+		if err := d.write(repeatCnt); err != nil {
+			return err
+		}
+	}
+}
+
+// читаем очередную руну. ok = false - вход закончился.
+func (d *decoder) readRune() (r rune, ok bool, err error) {
+	r, size, err := d.in.ReadRune()
+	switch {
+	case errors.Is(err, io.EOF):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("read input: %w", err)
 	}
 
-	// обрабаотываем крайний символ
-	if !isNumber(rArr[len(rArr)-1]) {
-		sBldr.WriteRune(rArr[len(rArr)-1])
+	d.last = d.next
+	d.next.runeOff++
+	d.next.byteOff += size
+
+	if d.opts.Digits == DigitsReject && unicode.IsDigit(r) && !isNumber(r) {
+		return 0, false, d.errorAt(ReasonUnicodeDigit, r)
 	}
 
-	return sBldr.String(), nil
+	return r, true, nil
+}
+
+// возвращаем последнюю прочитанную руну во вход.
+func (d *decoder) unreadRune() error {
+	d.next = d.last
+	return d.in.UnreadRune()
+}
+
+// собираем символ: руна r и следующие за ней комбинируемые руны (если включен CombiningCluster).
+func (d *decoder) readSymbol(r rune) error {
+	d.symbol = append(d.symbol[:0], r)
+	if d.opts.Combining != CombiningCluster {
+		return nil
+	}
+
+	for {
+		r, ok, err := d.readRune()
+		if err != nil || !ok {
+			return err
+		}
+		if !d.opts.isCombining(r) {
+			return d.unreadRune()
+		}
+		if len(d.symbol) > maxCombiningMarks {
+			return d.errorAt(ReasonTooManyCombining, r)
+		}
+		d.symbol = append(d.symbol, r)
+	}
+}
+
+// читаем количество повторов. если следующая руна не цифра - возвращаем её во вход, повтор - один.
+// без MultiDigit читаем не более одной цифры: следующая цифра даст ошибку "две цифры подряд".
+func (d *decoder) readCount() (int, error) {
+	repeatCnt, digits := 0, 0
+
+	for digits == 0 || d.opts.MultiDigit {
+		r, ok, err := d.readRune()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+
+		value, isDigit := d.opts.digitValue(r)
+		if !isDigit {
+			if err := d.unreadRune(); err != nil {
+				return 0, err
+			}
+			break
+		}
+
+		// контроль переполнения и максимального количества повторов
+		if repeatCnt > (math.MaxInt-value)/10 {
+			return 0, d.errorAt(ReasonRepeatTooLarge, r)
+		}
+		repeatCnt = repeatCnt*10 + value
+		digits++
+
+		if maxRepeat := d.opts.maxRepeat(); maxRepeat > 0 && repeatCnt > maxRepeat {
+			return 0, d.errorAt(ReasonRepeatTooLarge, r)
+		}
+	}
+
+	if digits == 0 {
+		return 1, nil
+	}
+	return repeatCnt, nil
+}
+
+// пишем текущий символ указанное число раз с контролем размера результата.
+func (d *decoder) write(repeatCnt int) error {
+	size := 0
+	for _, r := range d.symbol {
+		size += utf8.RuneLen(r)
+	}
+
+	// сравниваем через деление, чтобы не переполнить size * repeatCnt
+	if d.limit > 0 && int64(repeatCnt) > (d.limit-d.written)/int64(size) {
+		return &OutputLimitError{Limit: d.limit}
+	}
+
+	for i := 0; i < repeatCnt; i++ {
+		for _, r := range d.symbol {
+			if _, err := d.out.WriteRune(r); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+		}
+	}
+	d.written += int64(size) * int64(repeatCnt)
+
+	return nil
+}
+
+// ошибка распаковки в позиции последней прочитанной руны.
+// при ошибке в конце строки (ReasonEscapeAtEnd) последней прочитана руна экранирования - на неё и указываем.
+func (d *decoder) errorAt(reason Reason, r rune) error {
+	return &UnpackError{RuneOffset: d.last.runeOff, ByteOffset: d.last.byteOff, Rune: r, Reason: reason}
 }
 
 // ф-я проверки руны на цифру.
@@ -51,3 +212,8 @@ func isNumber(r rune) bool {
 	_, err := strconv.Atoi(string(r))
 	return err == nil
 }
+
+// ф-я проверки возможности экранирования руны (для Pack).
+func isEscapable(r rune) bool {
+	return isNumber(r) || r == escapeRune
+}
@@ -25,19 +25,19 @@ er3
 		{input: "  2  ", expected: "     "},
 		// non-printed symbols and escape symbols in string
 		{input: "aa2\n2\b3", expected: "aaa\n\n\b\b\b"},
-		{input: "aa2\\n2\\t3", expected: "aaa\\nn\\ttt"},
 		// encoded strings
 		{input: "\u65e53\u672c2\u8a9e", expected: "\u65e5\u65e5\u65e5\u672c\u672c\u8a9e"},
 		{input: "\U000065e52\U0000672c\U00008a9e2", expected: "\U000065e5\U000065e5\U0000672c\U00008a9e\U00008a9e"},
 		// backquoted strings
-		{input: `a2\n3\2a`, expected: `aa\nnn\\a`},
 		{input: testStr, expected: "test\n\n\nerrr\n\n  "},
 
-		// uncomment if task with asterisk completed
-		// {input: `qwe\4\5`, expected: `qwe45`},
-		// {input: `qwe\45`, expected: `qwe44444`},
-		// {input: `qwe\\5`, expected: `qwe\\\\\`},
-		// {input: `qwe\\\3`, expected: `qwe\3`},
+		// escaped digits and slashes
+		{input: `qwe\4\5`, expected: `qwe45`},
+		{input: `qwe\45`, expected: `qwe44444`},
+		{input: `qwe\\5`, expected: `qwe\\\\\`},
+		{input: `qwe\\\3`, expected: `qwe\3`},
+		{input: `\3abc`, expected: `3abc`},
+		{input: `\\0a`, expected: `a`},
 	}
 
 	for _, tc := range tests {
@@ -60,3 +60,46 @@ func TestUnpackInvalidString(t *testing.T) {
 		})
 	}
 }
+
+func TestUnpackInvalidEscape(t *testing.T) {
+	invalidStrings := []string{`qw\ne`, "aa2\\n2\\t3", `a2\n3\2a`, `abc\`, `a\\\`}
+	for _, tc := range invalidStrings {
+		tc := tc
+		t.Run(tc, func(t *testing.T) {
+			_, err := Unpack(tc)
+			require.Truef(t, errors.Is(err, ErrInvalidEscape), "actual error %q", err)
+			require.Truef(t, errors.Is(err, ErrInvalidString), "actual error %q", err)
+		})
+	}
+}
+
+func TestUnpackErrorPosition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected UnpackError
+	}{
+		{input: "3abc", expected: UnpackError{RuneOffset: 0, ByteOffset: 0, Rune: '3', Reason: ReasonStartFromNumber}},
+		{input: "aaa10b", expected: UnpackError{RuneOffset: 4, ByteOffset: 4, Rune: '0', Reason: ReasonTwoNumbersInRow}},
+		{input: "日本2語34", expected: UnpackError{RuneOffset: 5, ByteOffset: 11, Rune: '4', Reason: ReasonTwoNumbersInRow}},
+		{input: `qw\ne`, expected: UnpackError{RuneOffset: 3, ByteOffset: 3, Rune: 'n', Reason: ReasonInvalidEscape}},
+		{input: `語\語`, expected: UnpackError{RuneOffset: 2, ByteOffset: 4, Rune: '語', Reason: ReasonInvalidEscape}},
+		{input: `abc\`, expected: UnpackError{RuneOffset: 3, ByteOffset: 3, Rune: '\\', Reason: ReasonEscapeAtEnd}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Unpack(tc.input)
+
+			var unpackErr *UnpackError
+			require.ErrorAs(t, err, &unpackErr)
+			require.Equal(t, tc.expected, *unpackErr)
+			require.Truef(t, errors.Is(err, ErrInvalidString), "actual error %q", err)
+		})
+	}
+
+	t.Run("error message", func(t *testing.T) {
+		_, err := Unpack("aaa10b")
+		require.EqualError(t, err, `two numbers in a row '0' at rune 4 (byte 4): invalid string`)
+	})
+}
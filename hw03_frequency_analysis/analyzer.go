@@ -0,0 +1,198 @@
+package hw03frequencyanalysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// знаки препинания, которые по умолчанию считаются разделителями слов.
+// "–" в конце - это тире, а не дефис. дефис обрабатывается отдельно.
+const DefaultPunctuation = `.,!?'"()\/[]{}–`
+
+// Analyzer - настраиваемый частотный анализатор текста.
+// После создания не изменяется, поэтому его можно использовать из нескольких горутин.
+type Analyzer struct {
+	punct        string              // разделители слов помимо пробельных символов
+	unicodeSplit bool                // разделять по категориям unicode вместо набора punct
+	joiners      string              // знаки, которые при unicodeSplit остаются внутри слова
+	foldCase     bool                // приводить слова к нижнему регистру
+	stopWords    map[string]struct{} // исключаемые слова (в нижнем регистре)
+	minLen       int                 // минимальная длина слова в рунах
+	tieBreak     TieBreak            // порядок слов с одинаковой частотой
+	collator     Collator            // сравнение слов для TieBreakCollation
+	normalizer   Normalizer          // приведение слов к основе. nil - слова считаются как есть
+}
+
+// Option - параметр анализатора.
+type Option func(a *Analyzer)
+
+// WithPunctuation задает набор знаков, разделяющих слова (в дополнение к пробельным символам).
+func WithPunctuation(punct string) Option {
+	return func(a *Analyzer) {
+		a.punct = punct
+		a.unicodeSplit = false
+	}
+}
+
+// WithUnicodeSplit включает разделение слов по категориям unicode: разделителями считаются
+// все знаки препинания и символы (unicode.P, unicode.S), кроме joiners. joiners на краях слова отбрасываются:
+// при joiners = "-" "какой-то" - одно слово, а "-нога-" - это "нога".
+func WithUnicodeSplit(joiners string) Option {
+	return func(a *Analyzer) {
+		a.unicodeSplit = true
+		a.joiners = joiners
+	}
+}
+
+// WithCaseFolding включает или выключает приведение слов к нижнему регистру.
+func WithCaseFolding(fold bool) Option {
+	return func(a *Analyzer) {
+		a.foldCase = fold
+	}
+}
+
+// WithStopWords добавляет слова, которые не учитываются при подсчете. регистр стоп-слов не учитывается.
+func WithStopWords(words ...string) Option {
+	return func(a *Analyzer) {
+		for _, w := range words {
+			a.stopWords[strings.ToLower(w)] = struct{}{}
+		}
+	}
+}
+
+// WithMinLength задает минимальную длину слова в рунах. более короткие слова не учитываются.
+func WithMinLength(n int) Option {
+	return func(a *Analyzer) {
+		a.minLen = n
+	}
+}
+
+// WithNormalizer задает приведение слов к основе (например, стемминг). слова с одинаковой основой
+// считаются одним словом, в результате оно представлено самой частой словоформой.
+// стоп-слова и минимальная длина проверяются до приведения.
+func WithNormalizer(n Normalizer) Option {
+	return func(a *Analyzer) {
+		a.normalizer = n
+	}
+}
+
+// NewAnalyzer создает анализатор. без параметров анализатор работает как Top10:
+// разделители - пробельные символы и DefaultPunctuation, регистр не учитывается.
+func NewAnalyzer(opts ...Option) *Analyzer {
+	a := &Analyzer{
+		punct:     DefaultPunctuation,
+		foldCase:  true,
+		stopWords: make(map[string]struct{}),
+		collator:  BasicCollator{},
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// Words возвращает слова текста в порядке следования с учетом настроек анализатора.
+func (a *Analyzer) Words(text string) []string {
+	fields := strings.FieldsFunc(text, a.isSeparator)
+
+	wordsArr := fields[:0]
+	for _, f := range fields {
+		if w, ok := a.normalize(f); ok {
+			wordsArr = append(wordsArr, w)
+		}
+	}
+
+	return wordsArr
+}
+
+// Count возвращает словарь слово - количество вхождений.
+func (a *Analyzer) Count(text string) map[string]int {
+	stats := a.count(text)
+
+	mapStr := make(map[string]int, len(stats))
+	for word, st := range stats {
+		mapStr[st.representative(word)] = st.count
+	}
+
+	return mapStr
+}
+
+// Top возвращает n наиболее часто встречаемых слов. порядок слов с одинаковой частотой задается WithTieBreak.
+func (a *Analyzer) Top(text string, n int) []string {
+	wcArr := a.TopN(text, n)
+
+	outArr := make([]string, 0, len(wcArr))
+	for _, wc := range wcArr {
+		outArr = append(outArr, wc.Word)
+	}
+
+	return outArr
+}
+
+// TopN возвращает n наиболее часто встречаемых слов вместе с количеством вхождений.
+func (a *Analyzer) TopN(text string, n int) []WordCount {
+	return a.rank(a.count(text), n)
+}
+
+// считаем слова и запоминаем номер первого вхождения для TieBreakFirstOccurrence.
+func (a *Analyzer) count(text string) wordStats {
+	fields := strings.FieldsFunc(text, a.isSeparator)
+
+	stats := make(wordStats, len(fields))
+	pos := 0
+	for _, f := range fields {
+		if w, ok := a.normalize(f); ok {
+			a.addWord(stats.add, w, pos)
+			pos++
+		}
+	}
+
+	return stats
+}
+
+// учитываем слово функцией add. при заданном normalizer слово учитывается по основе,
+// а само слово запоминается как словоформа.
+func (a *Analyzer) addWord(add func(word string, pos int) *wordStat, word string, pos int) {
+	if a.normalizer == nil {
+		add(word, pos)
+		return
+	}
+	add(a.normalizer.Normalize(word), pos).addForm(word)
+}
+
+// руна - разделитель слов.
+func (a *Analyzer) isSeparator(r rune) bool {
+	if unicode.IsSpace(r) {
+		return true
+	}
+	if a.unicodeSplit {
+		return (unicode.IsPunct(r) || unicode.IsSymbol(r)) && !strings.ContainsRune(a.joiners, r)
+	}
+	return strings.ContainsRune(a.punct, r)
+}
+
+// приводим слово к виду для подсчета. ok = false - слово не учитывается.
+func (a *Analyzer) normalize(word string) (string, bool) {
+	if a.unicodeSplit {
+		word = strings.Trim(word, a.joiners)
+	}
+	if a.foldCase {
+		word = strings.ToLower(word)
+	}
+
+	// дефис словом не является
+	if word == "" || word == "-" {
+		return "", false
+	}
+	if a.minLen > 0 && utf8.RuneCountInString(word) < a.minLen {
+		return "", false
+	}
+	if _, ok := a.stopWords[strings.ToLower(word)]; ok {
+		return "", false
+	}
+
+	return word, true
+}
@@ -0,0 +1,129 @@
+package hw03frequencyanalysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer(t *testing.T) {
+	text := "Нога, нога! НОГА - какой-то dog,cat dog...cat \"нога\" — ---- и в на a the"
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{
+			name:     "default options",
+			opts:     nil,
+			expected: strings.Fields(`нога нога нога какой-то dog cat dog cat нога — ---- и в на a the`),
+		},
+		{
+			name:     "custom punctuation",
+			opts:     []Option{WithPunctuation(`,!"`)},
+			expected: strings.Fields(`нога нога нога какой-то dog cat dog...cat нога — ---- и в на a the`),
+		},
+		{
+			name:     "without case folding",
+			opts:     []Option{WithCaseFolding(false)},
+			expected: strings.Fields(`Нога нога НОГА какой-то dog cat dog cat нога — ---- и в на a the`),
+		},
+		{
+			name:     "unicode split",
+			opts:     []Option{WithUnicodeSplit("-")},
+			expected: strings.Fields(`нога нога нога какой-то dog cat dog cat нога и в на a the`),
+		},
+		{
+			name:     "stop words",
+			opts:     []Option{WithStopWords(StopWordsRussian...), WithStopWords(StopWordsEnglish...)},
+			expected: strings.Fields(`нога нога нога какой-то dog cat dog cat нога — ----`),
+		},
+		{
+			name:     "min length",
+			opts:     []Option{WithUnicodeSplit("-"), WithMinLength(4)},
+			expected: strings.Fields(`нога нога нога какой-то нога`),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, NewAnalyzer(tc.opts...).Words(text))
+		})
+	}
+}
+
+func TestAnalyzerTop(t *testing.T) {
+	t.Run("russian without stop words", func(t *testing.T) {
+		a := NewAnalyzer(WithStopWords(StopWordsRussian...))
+		expected := strings.Fields("кристофер робин винни-пух имя иногда теперь больше винни звал знает")
+		require.Equal(t, expected, a.Top(ruText, 10))
+	})
+
+	t.Run("english without stop words", func(t *testing.T) {
+		a := NewAnalyzer(WithStopWords(StopWordsEnglish...), WithMinLength(2))
+		expected := strings.Fields("go type interfaces typing class term checked conformance duck form")
+		require.Equal(t, expected, a.Top(enText, 10))
+	})
+
+	t.Run("zero and negative n", func(t *testing.T) {
+		require.Len(t, NewAnalyzer().Top(enText, 0), 0)
+		require.Len(t, NewAnalyzer().Top(enText, -1), 0)
+	})
+}
+
+func TestAnalyzerNormalizer(t *testing.T) {
+	text := "Нога ногу. Ноги, нога! Рука руки knitting knit knits"
+
+	t.Run("word forms merged", func(t *testing.T) {
+		a := NewAnalyzer(WithNormalizer(Stemmer{}))
+		expected := []WordCount{{"нога", 4}, {"knit", 3}, {"рука", 2}}
+		require.Equal(t, expected, a.TopN(text, 10))
+		require.Equal(t, map[string]int{"нога": 4, "knit": 3, "рука": 2}, a.Count(text))
+	})
+
+	t.Run("representative keeps case", func(t *testing.T) {
+		a := NewAnalyzer(WithNormalizer(Stemmer{}), WithCaseFolding(false))
+		require.Equal(t, []WordCount{{"Нога", 4}}, a.TopN(text, 1))
+	})
+
+	t.Run("stop words checked before normalization", func(t *testing.T) {
+		a := NewAnalyzer(WithNormalizer(Stemmer{}), WithStopWords("ноги"))
+		require.Equal(t, []WordCount{{"knit", 3}, {"нога", 3}}, a.TopN(text, 2))
+	})
+
+	t.Run("custom normalizer", func(t *testing.T) {
+		firstLetter := NormalizerFunc(func(w string) string { return string([]rune(w)[:1]) })
+		a := NewAnalyzer(WithNormalizer(firstLetter))
+		require.Equal(t, []WordCount{{"нога", 4}, {"knit", 3}, {"рука", 2}}, a.TopN(text, 10))
+	})
+
+	t.Run("counter", func(t *testing.T) {
+		a := NewAnalyzer(WithNormalizer(Stemmer{}))
+		c := NewCounter(a)
+		_, err := c.ReadFrom(strings.NewReader(text))
+		require.NoError(t, err)
+		require.Equal(t, a.TopN(text, 10), c.Top(10))
+
+		ac, err := NewApproxCounter(a, 10)
+		require.NoError(t, err)
+		_, err = ac.ReadFrom(strings.NewReader(text))
+		require.NoError(t, err)
+		require.Equal(t, a.TopN(text, 10), ac.Top(10))
+	})
+}
+
+func TestStopWords(t *testing.T) {
+	words, ok := StopWords("ru")
+	require.True(t, ok)
+	require.Equal(t, StopWordsRussian, words)
+
+	words, ok = StopWords("en")
+	require.True(t, ok)
+	require.Equal(t, StopWordsEnglish, words)
+
+	_, ok = StopWords("de")
+	require.False(t, ok)
+}
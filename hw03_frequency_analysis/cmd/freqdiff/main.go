@@ -0,0 +1,16 @@
+package main
+
+import "os"
+
+const usage = `Usage: freqdiff [flags] <old> <new>
+
+Сравнивает частоты слов двух текстов и выводит слова, которые появились, исчезли
+или сильнее всего изменили относительную частоту. Слова упорядочены по log-likelihood G².
+Вместо имени файла можно указать "-" - текст читается из stdin.
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	hw03frequencyanalysis "github.com/fixme_my_friend/hw03_frequency_analysis"
+	"github.com/fixme_my_friend/hw03_frequency_analysis/internal/cli"
+)
+
+// форматы вывода.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// config - параметры запуска.
+type config struct {
+	cli.AnalyzerFlags
+	format   string
+	n        int
+	minScore float64
+	oldFile  string
+	newFile  string
+}
+
+// report - результат сравнения. в формате json выводится целиком одним объектом.
+type report struct {
+	Old      string       `json:"old"`
+	New      string       `json:"new"`
+	TotalOld int          `json:"totalOld"`
+	TotalNew int          `json:"totalNew"`
+	Words    []wordRecord `json:"words"`
+}
+
+type wordRecord struct {
+	Word     string  `json:"word"`
+	Status   string  `json:"status"`
+	CountOld int     `json:"countOld"`
+	CountNew int     `json:"countNew"`
+	FreqOld  float64 `json:"freqOld"`
+	FreqNew  float64 `json:"freqNew"`
+	Score    float64 `json:"score"`
+}
+
+// run выполняет сравнение и возвращает код завершения.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	parse := func() (*config, error) { return parseArgs(args, stderr) }
+	return cli.Run(stderr, parse, func(cfg *config) error {
+		rep, err := compare(cfg, stdin)
+		if err != nil {
+			return err
+		}
+		return writeReport(cfg, rep, stdout)
+	})
+}
+
+func parseArgs(args []string, stderr io.Writer) (*config, error) {
+	cfg := &config{}
+
+	fs := cli.NewFlagSet("freqdiff", usage, stderr)
+	fs.StringVar(&cfg.format, "format", formatText, "output format: text or json")
+	fs.IntVar(&cfg.n, "n", 20, "number of words in report")
+	fs.Float64Var(&cfg.minScore, "min-score", 0, "skip words with log-likelihood below this value (3.84 - p < 0.05)")
+	cfg.Register(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return nil, fmt.Errorf("expected 2 files, got %d", fs.NArg())
+	}
+	cfg.oldFile, cfg.newFile = fs.Arg(0), fs.Arg(1)
+
+	if cfg.format != formatText && cfg.format != formatJSON {
+		return nil, fmt.Errorf("unknown output format %q", cfg.format)
+	}
+	if err := cli.CheckStdin([]string{cfg.oldFile, cfg.newFile}); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func compare(cfg *config, stdin io.Reader) (*report, error) {
+	a, err := cfg.Analyzer()
+	if err != nil {
+		return nil, err
+	}
+
+	oldCnt, err := countFile(a, cfg.oldFile, stdin)
+	if err != nil {
+		return nil, err
+	}
+	newCnt, err := countFile(a, cfg.newFile, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &report{
+		Old: cfg.oldFile, New: cfg.newFile,
+		TotalOld: oldCnt.Total(), TotalNew: newCnt.Total(),
+		Words: []wordRecord{},
+	}
+	for _, d := range hw03frequencyanalysis.DiffCounters(oldCnt, newCnt, cfg.n) {
+		if d.Score < cfg.minScore {
+			break
+		}
+		rep.Words = append(rep.Words, wordRecord{
+			Word: d.Word, Status: d.Status.String(),
+			CountOld: d.CountOld, CountNew: d.CountNew,
+			FreqOld: d.FreqOld, FreqNew: d.FreqNew,
+			Score: d.Score,
+		})
+	}
+	return rep, nil
+}
+
+func countFile(
+	a *hw03frequencyanalysis.Analyzer, name string, stdin io.Reader,
+) (*hw03frequencyanalysis.Counter, error) {
+	c := hw03frequencyanalysis.NewCounter(a)
+	err := cli.ReadSource(name, stdin, func(r io.Reader) error {
+		_, err := c.ReadFrom(r)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return c, nil
+}
+
+func writeReport(cfg *config, rep *report, stdout io.Writer) error {
+	out := bufio.NewWriter(stdout)
+
+	if cfg.format == formatJSON {
+		data, err := json.Marshal(rep)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s\n", data)
+		return out.Flush()
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "word\tstatus\told\tnew\told %%\tnew %%\tscore\n")
+	for _, w := range rep.Words {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.3f\t%.3f\t%.2f\n",
+			w.Word, w.Status, w.CountOld, w.CountNew, 100*w.FreqOld, 100*w.FreqNew, w.Score)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "total words: old %d, new %d\n", rep.TotalOld, rep.TotalNew)
+	return out.Flush()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fixme_my_friend/hw03_frequency_analysis/internal/cli"
+	"github.com/fixme_my_friend/hw03_frequency_analysis/internal/clitest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := clitest.WriteFile(t, dir, "old.txt", strings.Repeat("кот пес ", 10)+"кот кот мышь")
+	newFile := clitest.WriteFile(t, dir, "new.txt", strings.Repeat("Кот пес ", 10)+"пес пес слон")
+
+	t.Run("text", func(t *testing.T) {
+		code, stdout, stderr := clitest.Run(t, run, "", oldFile, newFile)
+		require.Equal(t, cli.ExitOK, code, stderr)
+		expected := "word  status       old  new  old %   new %   score\n" +
+			"мышь  disappeared  1    0    4.348   0.000   1.39\n" +
+			"слон  appeared     0    1    0.000   4.348   1.39\n" +
+			"кот   decreased    12   10   52.174  43.478  0.18\n" +
+			"пес   increased    10   12   43.478  52.174  0.18\n" +
+			"total words: old 23, new 23\n"
+		require.Equal(t, expected, stdout)
+	})
+
+	t.Run("json from stdin", func(t *testing.T) {
+		input := strings.Repeat("кот пес ", 10) + "пес пес слон"
+		code, stdout, stderr := clitest.Run(t, run, input, "-format", "json", "-n", "2", oldFile, "-")
+		require.Equal(t, cli.ExitOK, code, stderr)
+
+		var rep report
+		require.NoError(t, json.Unmarshal([]byte(stdout), &rep))
+		require.Equal(t, "-", rep.New)
+		require.Equal(t, 23, rep.TotalNew)
+		require.Len(t, rep.Words, 2)
+		require.Equal(t, "мышь", rep.Words[0].Word)
+		require.Equal(t, "disappeared", rep.Words[0].Status)
+	})
+
+	t.Run("min score", func(t *testing.T) {
+		code, stdout, _ := clitest.Run(t, run, "", "-format=json", "-min-score", "1", oldFile, newFile)
+		require.Equal(t, cli.ExitOK, code)
+		require.Contains(t, stdout, `"words":[{"word":"мышь"`)
+		require.NotContains(t, stdout, "кот")
+	})
+
+	t.Run("case", func(t *testing.T) {
+		code, stdout, _ := clitest.Run(t, run, "", "-fold-case=false", oldFile, newFile)
+		require.Equal(t, cli.ExitOK, code)
+		require.Contains(t, stdout, "Кот")
+	})
+
+	t.Run("stem and stop words", func(t *testing.T) {
+		oldFile := clitest.WriteFile(t, dir, "old-forms.txt", "нога и ноги")
+		newFile := clitest.WriteFile(t, dir, "new-forms.txt", "ногу и рука")
+		code, stdout, _ := clitest.Run(t, run, "", "-format=json", "-stem", "-stop-words", "ru,en", oldFile, newFile)
+		require.Equal(t, cli.ExitOK, code)
+
+		var rep report
+		require.NoError(t, json.Unmarshal([]byte(stdout), &rep))
+		require.Equal(t, 2, rep.TotalOld)
+		require.Equal(t, []wordRecord{
+			{Word: "рука", Status: "appeared", CountNew: 1, FreqNew: 0.5, Score: rep.Words[0].Score},
+			{
+				Word: "нога", Status: "decreased", CountOld: 2, CountNew: 1, FreqOld: 1, FreqNew: 0.5,
+				Score: rep.Words[1].Score,
+			},
+		}, rep.Words)
+	})
+}
+
+func TestRunErrors(t *testing.T) {
+	file := clitest.WriteFile(t, t.TempDir(), "a.txt", "a")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no files", args: nil},
+		{name: "one file", args: []string{file}},
+		{name: "unknown format", args: []string{"-format", "xml", file, file}},
+		{name: "unknown stop words", args: []string{"-stop-words", "de", file, file}},
+		{name: "stdin twice", args: []string{"-", "-"}},
+		{name: "missing file", args: []string{file, file + ".missing"}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			code, stdout, stderr := clitest.Run(t, run, "", tc.args...)
+			require.Equal(t, cli.ExitUsage, code)
+			require.Empty(t, stdout)
+			require.NotEmpty(t, stderr)
+		})
+	}
+}
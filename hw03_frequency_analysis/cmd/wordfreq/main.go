@@ -0,0 +1,16 @@
+package main
+
+import "os"
+
+const usage = `Usage: wordfreq [flags] [files...]
+
+Выводит наиболее часто встречаемые слова или n-граммы текстов.
+Файлы можно задавать шаблонами (*.txt). Если файлы не указаны или указан "-" - текст читается из stdin.
+С флагом -per-file кроме общего результата выводится результат по каждому файлу.
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
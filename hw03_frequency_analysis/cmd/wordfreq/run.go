@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	hw03frequencyanalysis "github.com/fixme_my_friend/hw03_frequency_analysis"
+	"github.com/fixme_my_friend/hw03_frequency_analysis/internal/cli"
+)
+
+// название stdin в результате.
+const stdinName = "stdin"
+
+// название общего результата в табличном выводе и csv.
+const totalName = "(total)"
+
+// форматы вывода.
+const (
+	formatTable = "table"
+	formatCSV   = "csv"
+	formatJSON  = "json"
+)
+
+// config - параметры запуска.
+type config struct {
+	cli.AnalyzerFlags
+	format  string
+	n       int
+	ngram   int
+	perFile bool
+	files   []string
+}
+
+// result - наиболее частые слова одного файла или всех файлов вместе.
+type result struct {
+	File  string       `json:"file,omitempty"`
+	Total int          `json:"total"` // количество учтенных слов (n-грамм)
+	Top   []wordRecord `json:"top"`
+}
+
+type wordRecord struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// report - результат запуска. в формате json выводится целиком одним объектом.
+type report struct {
+	Files []result `json:"files,omitempty"`
+	Total result   `json:"total"`
+}
+
+// run выполняет подсчет и возвращает код завершения.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	parse := func() (*config, error) { return parseArgs(args, stderr) }
+	return cli.Run(stderr, parse, func(cfg *config) error {
+		rep, err := count(cfg, stdin)
+		if err != nil {
+			return err
+		}
+		return writeReport(cfg, rep, stdout)
+	})
+}
+
+func parseArgs(args []string, stderr io.Writer) (*config, error) {
+	cfg := &config{}
+
+	fs := cli.NewFlagSet("wordfreq", usage, stderr)
+	fs.StringVar(&cfg.format, "format", formatTable, "output format: table, csv or json")
+	fs.IntVar(&cfg.n, "n", 10, "number of most frequent words")
+	fs.IntVar(&cfg.ngram, "ngram", 1, "count n-grams of this many words, 1 - single words")
+	fs.BoolVar(&cfg.perFile, "per-file", false, "print results for each file besides the total")
+	cfg.Register(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch cfg.format {
+	case formatTable, formatCSV, formatJSON:
+	default:
+		return nil, fmt.Errorf("unknown output format %q", cfg.format)
+	}
+	if cfg.ngram <= 0 {
+		return nil, fmt.Errorf("invalid n-gram size %d", cfg.ngram)
+	}
+	// n-граммы не приводятся к основе (Counter), и флаг -stem был бы проигнорирован
+	if cfg.Stem && cfg.ngram > 1 {
+		return nil, errors.New("-stem cannot be used with -ngram greater than 1")
+	}
+
+	var err error
+	if cfg.files, err = expandFiles(fs.Args()); err != nil {
+		return nil, err
+	}
+	if err := cli.CheckStdin(cfg.files); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// раскрываем шаблоны имен файлов. без файлов читается stdin.
+func expandFiles(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{cli.StdinArg}, nil
+	}
+
+	files := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == cli.StdinArg || !strings.ContainsAny(arg, "*?[") {
+			files = append(files, arg)
+			continue
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", arg)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+func count(cfg *config, stdin io.Reader) (*report, error) {
+	a, err := cfg.Analyzer()
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := hw03frequencyanalysis.NewNGramCounter(a, cfg.ngram)
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &report{}
+	for _, name := range cfg.files {
+		err := cli.ReadSource(name, stdin, func(r io.Reader) error {
+			if !cfg.perFile {
+				_, err := total.ReadFrom(r)
+				return err
+			}
+
+			// файл читается один раз в свой счетчик, который затем добавляется к общему
+			fileCnt, err := hw03frequencyanalysis.NewNGramCounter(a, cfg.ngram)
+			if err != nil {
+				return err
+			}
+			if _, err := fileCnt.ReadFrom(r); err != nil {
+				return err
+			}
+			if err := total.Merge(fileCnt); err != nil {
+				return err
+			}
+			rep.Files = append(rep.Files, makeResult(sourceName(name), fileCnt, cfg.n))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sourceName(name), err)
+		}
+	}
+	rep.Total = makeResult("", total, cfg.n)
+
+	return rep, nil
+}
+
+func sourceName(name string) string {
+	if name == cli.StdinArg {
+		return stdinName
+	}
+	return name
+}
+
+func makeResult(file string, c *hw03frequencyanalysis.Counter, n int) result {
+	res := result{File: file, Total: c.Total(), Top: []wordRecord{}}
+	for _, wc := range c.Top(n) {
+		res.Top = append(res.Top, wordRecord{Word: wc.Word, Count: wc.Count})
+	}
+	return res
+}
+
+// результаты для вывода строками: сначала по файлам, затем общий.
+func (rep *report) results() []result {
+	total := rep.Total
+	total.File = totalName
+	results := make([]result, 0, len(rep.Files)+1)
+	return append(append(results, rep.Files...), total)
+}
+
+func writeReport(cfg *config, rep *report, out io.Writer) error {
+	switch cfg.format {
+	case formatJSON:
+		data, err := json.Marshal(rep)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+
+	case formatCSV:
+		w := csv.NewWriter(out)
+		_ = w.Write([]string{"file", "rank", "word", "count"})
+		for _, res := range rep.results() {
+			for i, wr := range res.Top {
+				_ = w.Write([]string{res.File, strconv.Itoa(i + 1), wr.Word, strconv.Itoa(wr.Count)})
+			}
+		}
+		w.Flush()
+		return w.Error()
+
+	default:
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		if cfg.perFile {
+			fmt.Fprint(tw, "file\t")
+		}
+		fmt.Fprintln(tw, "rank\tword\tcount")
+		for _, res := range rep.results() {
+			for i, wr := range res.Top {
+				if cfg.perFile {
+					fmt.Fprintf(tw, "%s\t", res.File)
+				}
+				fmt.Fprintf(tw, "%d\t%s\t%d\n", i+1, wr.Word, wr.Count)
+			}
+		}
+		return tw.Flush()
+	}
+}
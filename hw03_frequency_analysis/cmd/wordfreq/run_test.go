@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fixme_my_friend/hw03_frequency_analysis/internal/cli"
+	"github.com/fixme_my_friend/hw03_frequency_analysis/internal/clitest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	fileA := clitest.WriteFile(t, dir, "a.txt", "кот пес кот и мышь")
+	fileB := clitest.WriteFile(t, dir, "b.txt", "Пес пес кот и слон")
+
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+	}{
+		{
+			name: "stdin table", args: []string{"-n", "2"}, input: "a b b c c c",
+			expected: "rank  word  count\n1     c     3\n2     b     2\n",
+		},
+		{
+			name: "glob csv", args: []string{"-format", "csv", "-n", "2", filepath.Join(dir, "*.txt")},
+			expected: "file,rank,word,count\n(total),1,кот,3\n(total),2,пес,3\n",
+		},
+		{
+			name: "stop words and stdin", args: []string{"-stop-words", "ru", "-format=csv", "-per-file", fileA, "-"},
+			input: "и и и мышь",
+			expected: "file,rank,word,count\n" +
+				fileA + ",1,кот,2\n" + fileA + ",2,мышь,1\n" + fileA + ",3,пес,1\n" +
+				"stdin,1,мышь,1\n" +
+				"(total),1,кот,2\n(total),2,мышь,2\n(total),3,пес,1\n",
+		},
+		{
+			name: "bigrams", args: []string{"-ngram", "2", "-n", "1", "-format", "csv", fileA, fileB},
+			expected: "file,rank,word,count\n(total),1,кот и,2\n",
+		},
+		{
+			name:     "case and min length",
+			args:     []string{"-fold-case=false", "-min-length", "3", "-format", "csv", fileB},
+			expected: "file,rank,word,count\n(total),1,Пес,1\n(total),2,кот,1\n(total),3,пес,1\n(total),4,слон,1\n",
+		},
+		{
+			name: "stem", args: []string{"-stem", "-format", "csv", "-n", "1"}, input: "нога ноги ногу рука",
+			expected: "file,rank,word,count\n(total),1,нога,3\n",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			code, stdout, stderr := clitest.Run(t, run, tc.input, tc.args...)
+			require.Equal(t, cli.ExitOK, code, stderr)
+			require.Equal(t, tc.expected, stdout)
+		})
+	}
+
+	t.Run("per file table", func(t *testing.T) {
+		code, stdout, stderr := clitest.Run(t, run, "", "-per-file", "-n", "1", fileA, fileB)
+		require.Equal(t, cli.ExitOK, code, stderr)
+
+		lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+		require.Len(t, lines, 4)
+		require.Equal(t, []string{"file", "rank", "word", "count"}, strings.Fields(lines[0]))
+		require.Equal(t, []string{fileA, "1", "кот", "2"}, strings.Fields(lines[1]))
+		require.Equal(t, []string{fileB, "1", "пес", "2"}, strings.Fields(lines[2]))
+		require.Equal(t, []string{"(total)", "1", "кот", "3"}, strings.Fields(lines[3]))
+		// колонки выровнены
+		require.Equal(t, strings.Index(lines[0], "rank"), strings.Index(lines[3], "1"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		code, stdout, stderr := clitest.Run(t, run, "", "-format", "json", "-per-file", "-n", "1", fileA, fileB)
+		require.Equal(t, cli.ExitOK, code, stderr)
+
+		var rep report
+		require.NoError(t, json.Unmarshal([]byte(stdout), &rep))
+		require.Equal(t, report{
+			Files: []result{
+				{File: fileA, Total: 5, Top: []wordRecord{{"кот", 2}}},
+				{File: fileB, Total: 5, Top: []wordRecord{{"пес", 2}}},
+			},
+			Total: result{Total: 10, Top: []wordRecord{{"кот", 3}}},
+		}, rep)
+	})
+}
+
+func TestRunErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unknown format", args: []string{"-format", "xml"}},
+		{name: "invalid n-gram size", args: []string{"-ngram", "0"}},
+		{name: "stemmed n-grams", args: []string{"-stem", "-ngram", "2"}},
+		{name: "unknown stop words", args: []string{"-stop-words", "de"}},
+		{name: "no glob matches", args: []string{filepath.Join(dir, "*.txt")}},
+		{name: "bad glob", args: []string{"[a-"}},
+		{name: "missing file", args: []string{filepath.Join(dir, "missing.txt")}},
+		{name: "stdin twice", args: []string{"-", "-"}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			code, stdout, stderr := clitest.Run(t, run, "", tc.args...)
+			require.Equal(t, cli.ExitUsage, code)
+			require.Empty(t, stdout)
+			require.NotEmpty(t, stderr)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package hw03frequencyanalysis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// максимальная длина слова в байтах при потоковом чтении.
+const maxWordSize = 1024 * 1024
+
+var (
+	ErrInvalidCapacity = errors.New("capacity must be greater than zero")
+	ErrInvalidSize     = errors.New("n-gram size must be greater than zero")
+	ErrMergeCounters   = errors.New("counters cannot be merged")
+)
+
+// Counter - потоковый счетчик слов. читает текст из io.Reader частями,
+// не загружая его в память целиком. слова разбираются по правилам анализатора.
+// Counter не предназначен для одновременного использования из нескольких горутин.
+type Counter struct {
+	a      *Analyzer
+	exact  wordStats    // точный подсчет. память пропорциональна количеству разных слов
+	approx *spaceSaving // приближенный подсчет с ограниченной памятью
+	total  int          // количество учтенных слов (n-грамм)
+	size   int          // размер n-граммы. 1 - считаются слова
+	window []string     // последние слова текущего источника для составления n-грамм
+}
+
+// NewCounter создает счетчик с точным подсчетом. результат Top совпадает с Analyzer.TopN по тому же тексту.
+func NewCounter(a *Analyzer) *Counter {
+	return &Counter{a: a, exact: make(wordStats), size: 1}
+}
+
+// NewNGramCounter создает счетчик n-грамм из size слов с точным подсчетом.
+// результат Top совпадает с Analyzer.TopNGrams по тому же тексту.
+func NewNGramCounter(a *Analyzer, size int) (*Counter, error) {
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+	return &Counter{a: a, exact: make(wordStats), size: size}, nil
+}
+
+// NewApproxCounter создает приближенный счетчик (алгоритм Space-Saving), хранящий не более capacity слов.
+//
+// Гарантии для N учтенных слов:
+//   - оценка количества слова не меньше точного и превышает его не более чем на ErrorBound() <= N/capacity;
+//   - любое слово, встретившееся больше N/capacity раз, попадает в результат Top(capacity).
+//
+// Чем больше capacity по сравнению с n в Top(n), тем точнее порядок слов в результате.
+func NewApproxCounter(a *Analyzer, capacity int) (*Counter, error) {
+	if capacity <= 0 {
+		return nil, ErrInvalidCapacity
+	}
+	return &Counter{a: a, approx: newSpaceSaving(capacity), size: 1}, nil
+}
+
+// ReadFrom читает текст из r до конца и учитывает его слова. повторные вызовы дополняют статистику,
+// при этом слово на стыке двух источников считается двумя разными словами, а n-граммы не переходят
+// из одного источника в другой.
+func (c *Counter) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	c.window = c.window[:0]
+
+	scanner := bufio.NewScanner(cr)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxWordSize)
+	scanner.Split(scanWords(c.a.isSeparator))
+
+	for scanner.Scan() {
+		if w, ok := c.a.normalize(scanner.Text()); ok {
+			c.push(w)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return cr.n, fmt.Errorf("read text: %w", err)
+	}
+	return cr.n, nil
+}
+
+// Merge добавляет к статистике счетчика статистику other, как если бы текст other был прочитан
+// следующим источником. так один прочитанный текст учитывается в нескольких счетчиках без повторного чтения.
+// объединяются только точные счетчики с одним анализатором и размером n-грамм, иначе - ErrMergeCounters.
+func (c *Counter) Merge(other *Counter) error {
+	switch {
+	case c.approx != nil || other.approx != nil:
+		return fmt.Errorf("%w: approximate counter", ErrMergeCounters)
+	case c.a != other.a:
+		return fmt.Errorf("%w: different analyzers", ErrMergeCounters)
+	case c.size != other.size:
+		return fmt.Errorf("%w: n-gram sizes %d and %d", ErrMergeCounters, c.size, other.size)
+	}
+
+	// номера вхождений other идут после учтенных слов. статистику копируем, чтобы счетчики не делили ее
+	for word, st := range other.exact {
+		shifted := *st
+		shifted.first += c.total
+		m, ok := c.exact[word]
+		if !ok {
+			m = &wordStat{first: shifted.first}
+			c.exact[word] = m
+		}
+		m.merge(&shifted)
+	}
+	c.total += other.total
+	return nil
+}
+
+// Top возвращает n наиболее часто встречаемых слов. для приближенного счетчика количество - оценка сверху.
+func (c *Counter) Top(n int) []WordCount {
+	return c.a.rank(c.stats(), n)
+}
+
+// Total возвращает количество учтенных слов (для счетчика n-грамм - количество n-грамм).
+func (c *Counter) Total() int {
+	return c.total
+}
+
+// ErrorBound возвращает максимальное завышение количества слова в Top. для точного счетчика - 0.
+func (c *Counter) ErrorBound() int {
+	if c.approx != nil {
+		return c.approx.errorBound()
+	}
+	return 0
+}
+
+// статистика по учтенным словам. для приближенного счетчика - по отслеживаемым словам.
+func (c *Counter) stats() wordStats {
+	if c.approx != nil {
+		return c.approx.stats()
+	}
+	return c.exact
+}
+
+// учитываем слово. для счетчика n-грамм слово добавляется в окно и учитывается n-грамма из последних size слов.
+func (c *Counter) push(word string) {
+	if c.size <= 1 {
+		c.add(word)
+		return
+	}
+
+	c.window = append(c.window, word)
+	if len(c.window) < c.size {
+		return
+	}
+	c.add(strings.Join(c.window, ngramSeparator))
+	c.window = c.window[:copy(c.window, c.window[1:])]
+}
+
+func (c *Counter) add(word string) {
+	switch {
+	case c.approx != nil:
+		c.a.addWord(c.approx.add, word, c.total)
+	case c.size > 1:
+		// n-граммы не приводятся к основе, как в TopNGrams
+		c.exact.add(word, c.total)
+	default:
+		c.a.addWord(c.exact.add, word, c.total)
+	}
+	c.total++
+}
+
+// reader, считающий прочитанные байты для ReadFrom.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// функция разбиения потока на слова для bufio.Scanner. аналог bufio.ScanWords с произвольными разделителями.
+func scanWords(isSeparator func(r rune) bool) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		// пропускаем разделители в начале
+		start := 0
+		for width := 0; start < len(data); start += width {
+			// неполную руну в конце порции дочитываем: иначе она декодируется как utf8.RuneError,
+			// который при WithUnicodeSplit считается разделителем
+			if !atEOF && !utf8.FullRune(data[start:]) {
+				return start, nil, nil
+			}
+			var r rune
+			r, width = utf8.DecodeRune(data[start:])
+			if !isSeparator(r) {
+				break
+			}
+		}
+
+		// ищем конец слова
+		for width, i := 0, start; i < len(data); i += width {
+			if !atEOF && !utf8.FullRune(data[i:]) {
+				return start, nil, nil
+			}
+			var r rune
+			r, width = utf8.DecodeRune(data[i:])
+			if isSeparator(r) {
+				return i + width, data[start:i], nil
+			}
+		}
+
+		// слово до конца данных
+		if atEOF && len(data) > start {
+			return len(data), data[start:], nil
+		}
+
+		// запрашиваем следующую порцию данных
+		return start, nil, nil
+	}
+}
@@ -0,0 +1,201 @@
+package hw03frequencyanalysis
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter(t *testing.T) {
+	t.Run("same result as Top10", func(t *testing.T) {
+		for _, text := range []string{"", ".,!?", ruText, enText, sanskritTest} {
+			c := NewCounter(NewAnalyzer())
+			// читаем по одному байту, чтобы проверить слова и руны на границах буфера
+			n, err := c.ReadFrom(iotest.OneByteReader(strings.NewReader(text)))
+			require.NoError(t, err)
+			require.Equal(t, int64(len(text)), n)
+
+			require.Equal(t, Top10(text), wordsOf(c.Top(10)))
+			require.Equal(t, 0, c.ErrorBound())
+		}
+	})
+
+	t.Run("same result as TopN with options", func(t *testing.T) {
+		a := NewAnalyzer(
+			WithUnicodeSplit("-"), WithStopWords(StopWordsRussian...), WithTieBreak(TieBreakFirstOccurrence))
+		c := NewCounter(a)
+		_, err := c.ReadFrom(strings.NewReader(ruText))
+		require.NoError(t, err)
+		require.Equal(t, a.TopN(ruText, 20), c.Top(20))
+	})
+
+	t.Run("unicode split by one byte", func(t *testing.T) {
+		// руны, разрезанные границей чтения, не считаются разделителями
+		for _, text := range []string{"ёжик ёжик нога", ruText} {
+			a := NewAnalyzer(WithUnicodeSplit("-"))
+			c := NewCounter(a)
+			_, err := c.ReadFrom(iotest.OneByteReader(strings.NewReader(text)))
+			require.NoError(t, err)
+			require.Equal(t, a.TopN(text, 20), c.Top(20))
+		}
+	})
+
+	t.Run("several readers", func(t *testing.T) {
+		c := NewCounter(NewAnalyzer())
+		for _, part := range []string{"cat dog ", "dog cat ", "dog"} {
+			_, err := c.ReadFrom(strings.NewReader(part))
+			require.NoError(t, err)
+		}
+		require.Equal(t, []WordCount{{"dog", 3}, {"cat", 2}}, c.Top(10))
+		require.Equal(t, 5, c.Total())
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		errRead := errors.New("read failed")
+		_, err := NewCounter(NewAnalyzer()).ReadFrom(iotest.ErrReader(errRead))
+		require.ErrorIs(t, err, errRead)
+	})
+}
+
+func TestApproxCounter(t *testing.T) {
+	t.Run("invalid capacity", func(t *testing.T) {
+		_, err := NewApproxCounter(NewAnalyzer(), 0)
+		require.ErrorIs(t, err, ErrInvalidCapacity)
+	})
+
+	t.Run("exact while words fit capacity", func(t *testing.T) {
+		c, err := NewApproxCounter(NewAnalyzer(), 1000)
+		require.NoError(t, err)
+		_, err = c.ReadFrom(strings.NewReader(enText))
+		require.NoError(t, err)
+		require.Equal(t, TopN(enText, 10), c.Top(10))
+		require.Equal(t, 0, c.ErrorBound())
+	})
+
+	t.Run("error bounds", func(t *testing.T) {
+		// поток со степенным распределением частот слов
+		rnd := rand.New(rand.NewSource(1))
+		zipf := rand.NewZipf(rnd, 1.2, 1, 10000)
+		text := bytes.Buffer{}
+		exact := make(map[string]int)
+		for i := 0; i < 100_000; i++ {
+			w := fmt.Sprintf("w%d", zipf.Uint64())
+			exact[w]++
+			text.WriteString(w + " ")
+		}
+
+		capacity := 200
+		c, err := NewApproxCounter(NewAnalyzer(), capacity)
+		require.NoError(t, err)
+		textStr := text.String()
+		_, err = c.ReadFrom(&text)
+		require.NoError(t, err)
+
+		total := c.Total()
+		require.Equal(t, 100_000, total)
+		require.LessOrEqual(t, c.ErrorBound(), total/capacity)
+
+		top := c.Top(capacity)
+		found := make(map[string]int, len(top))
+		for _, wc := range top {
+			found[wc.Word] = wc.Count
+			// оценка не меньше точного значения и завышена не более чем на ErrorBound
+			require.GreaterOrEqual(t, wc.Count, exact[wc.Word], wc.Word)
+			require.LessOrEqual(t, wc.Count-exact[wc.Word], c.ErrorBound(), wc.Word)
+		}
+		// все слова с частотой больше N/capacity найдены
+		for w, cnt := range exact {
+			if cnt > total/capacity {
+				require.Contains(t, found, w)
+			}
+		}
+		// самые частые слова совпадают с точным подсчетом
+		require.Equal(t, NewAnalyzer().Top(textStr, 5), wordsOf(c.Top(5)))
+	})
+}
+
+func wordsOf(wcArr []WordCount) []string {
+	words := make([]string, 0, len(wcArr))
+	for _, wc := range wcArr {
+		words = append(words, wc.Word)
+	}
+	return words
+}
+
+func TestNGramCounter(t *testing.T) {
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := NewNGramCounter(NewAnalyzer(), 0)
+		require.ErrorIs(t, err, ErrInvalidSize)
+	})
+
+	t.Run("same result as TopNGrams", func(t *testing.T) {
+		a := NewAnalyzer(WithStopWords(StopWordsRussian...), WithTieBreak(TieBreakFirstOccurrence))
+		for _, size := range []int{1, 2, 3} {
+			c, err := NewNGramCounter(a, size)
+			require.NoError(t, err)
+			_, err = c.ReadFrom(iotest.OneByteReader(strings.NewReader(ruText)))
+			require.NoError(t, err)
+			require.Equal(t, a.TopNGrams(ruText, size, 20), c.Top(20), "size %d", size)
+		}
+	})
+
+	t.Run("n-grams do not cross readers", func(t *testing.T) {
+		c, err := NewNGramCounter(NewAnalyzer(), 2)
+		require.NoError(t, err)
+		for _, part := range []string{"cat dog", "dog cat", "dog"} {
+			_, err := c.ReadFrom(strings.NewReader(part))
+			require.NoError(t, err)
+		}
+		require.Equal(t, []WordCount{{"cat dog", 1}, {"dog cat", 1}}, c.Top(10))
+		require.Equal(t, 2, c.Total())
+	})
+}
+
+func TestCounterMerge(t *testing.T) {
+	t.Run("same result as reading sources in turn", func(t *testing.T) {
+		a := NewAnalyzer(WithNormalizer(Stemmer{}), WithTieBreak(TieBreakFirstOccurrence))
+		sequential := NewCounter(a)
+		merged := NewCounter(a)
+		for _, text := range []string{ruText, enText, ruText} {
+			_, err := sequential.ReadFrom(strings.NewReader(text))
+			require.NoError(t, err)
+
+			part := NewCounter(a)
+			_, err = part.ReadFrom(strings.NewReader(text))
+			require.NoError(t, err)
+			require.NoError(t, merged.Merge(part))
+		}
+		require.Equal(t, sequential.Top(30), merged.Top(30))
+		require.Equal(t, sequential.Total(), merged.Total())
+	})
+
+	t.Run("merged counter is not changed", func(t *testing.T) {
+		a := NewAnalyzer()
+		total, part := NewCounter(a), NewCounter(a)
+		_, err := part.ReadFrom(strings.NewReader("cat dog cat"))
+		require.NoError(t, err)
+		require.NoError(t, total.Merge(part))
+		require.NoError(t, total.Merge(part))
+
+		require.Equal(t, []WordCount{{"cat", 4}, {"dog", 2}}, total.Top(10))
+		require.Equal(t, []WordCount{{"cat", 2}, {"dog", 1}}, part.Top(10))
+	})
+
+	t.Run("incompatible counters", func(t *testing.T) {
+		a := NewAnalyzer()
+		approx, err := NewApproxCounter(a, 10)
+		require.NoError(t, err)
+		bigrams, err := NewNGramCounter(a, 2)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, NewCounter(a).Merge(approx), ErrMergeCounters)
+		require.ErrorIs(t, NewCounter(a).Merge(bigrams), ErrMergeCounters)
+		require.ErrorIs(t, NewCounter(a).Merge(NewCounter(NewAnalyzer())), ErrMergeCounters)
+	})
+}
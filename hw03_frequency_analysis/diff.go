@@ -0,0 +1,159 @@
+package hw03frequencyanalysis
+
+import (
+	"math"
+	"sort"
+)
+
+// DiffStatus - изменение слова между старым и новым текстом.
+type DiffStatus uint
+
+const (
+	DiffUnchanged   DiffStatus = iota // относительная частота не изменилась
+	DiffIncreased                     // относительная частота выросла
+	DiffDecreased                     // относительная частота уменьшилась
+	DiffAppeared                      // слово есть только в новом тексте
+	DiffDisappeared                   // слово есть только в старом тексте
+)
+
+// мапинг статусов на названия.
+var diffStatusNames = map[DiffStatus]string{
+	DiffUnchanged:   "unchanged",
+	DiffIncreased:   "increased",
+	DiffDecreased:   "decreased",
+	DiffAppeared:    "appeared",
+	DiffDisappeared: "disappeared",
+}
+
+func (s DiffStatus) String() string {
+	if name, ok := diffStatusNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// WordDiff - изменение частоты слова между старым и новым текстом.
+type WordDiff struct {
+	Word     string
+	Status   DiffStatus
+	CountOld int     // количество вхождений в старый текст
+	CountNew int     // количество вхождений в новый текст
+	FreqOld  float64 // относительная частота в старом тексте - доля от всех слов
+	FreqNew  float64 // относительная частота в новом тексте
+	Score    float64 // log-likelihood G². чем больше, тем менее вероятно, что изменение случайно
+}
+
+// Diff сравнивает частоты слов двух текстов и возвращает n слов с наиболее значимым изменением.
+func Diff(oldText, newText string, n int, opts ...Option) []WordDiff {
+	return NewAnalyzer(opts...).Diff(oldText, newText, n)
+}
+
+// Diff сравнивает частоты слов двух текстов и возвращает n слов с наиболее значимым изменением.
+// слова упорядочены по убыванию Score (log-likelihood G², Dunning 1993), при равенстве - лексикографически.
+// G² учитывает и изменение доли слова, и количество наблюдений: редкое слово, встретившееся
+// один раз вместо двух, получает меньший Score, чем частое слово с тем же относительным изменением.
+// при 1 степени свободы G² > 3.84 соответствует уровню значимости 5%, G² > 10.83 - 0.1%.
+func (a *Analyzer) Diff(oldText, newText string, n int) []WordDiff {
+	oldStats, newStats := a.count(oldText), a.count(newText)
+	return diffStats(oldStats, oldStats.total(), newStats, newStats.total(), n)
+}
+
+// DiffCounters сравнивает частоты слов, учтенных счетчиками, аналогично Analyzer.Diff.
+// для приближенных счетчиков сравниваются только отслеживаемые слова, количества - оценки сверху.
+func DiffCounters(oldCnt, newCnt *Counter, n int) []WordDiff {
+	return diffStats(oldCnt.stats(), oldCnt.total, newCnt.stats(), newCnt.total, n)
+}
+
+// сравниваем словари. totalOld, totalNew - количество всех слов текстов.
+func diffStats(oldStats wordStats, totalOld int, newStats wordStats, totalNew int, n int) []WordDiff {
+	if n <= 0 {
+		return []WordDiff{}
+	}
+
+	// объединяем статистику, чтобы выбрать представителя слова по словоформам обоих текстов
+	words := make(map[string]*wordStat, len(oldStats)+len(newStats))
+	for _, ws := range []wordStats{oldStats, newStats} {
+		for word, st := range ws {
+			if words[word] == nil {
+				words[word] = &wordStat{}
+			}
+			words[word].merge(st)
+		}
+	}
+
+	diffArr := make([]WordDiff, 0, len(words))
+	for word, st := range words {
+		d := WordDiff{Word: st.representative(word)}
+		if old, ok := oldStats[word]; ok {
+			d.CountOld = old.count
+		}
+		if cur, ok := newStats[word]; ok {
+			d.CountNew = cur.count
+		}
+		d.FreqOld = frequency(d.CountOld, totalOld)
+		d.FreqNew = frequency(d.CountNew, totalNew)
+		d.Status = diffStatus(d.CountOld, totalOld, d.CountNew, totalNew)
+		d.Score = logLikelihood(d.CountOld, totalOld, d.CountNew, totalNew)
+		diffArr = append(diffArr, d)
+	}
+
+	sort.Slice(diffArr, func(i, j int) bool {
+		if diffArr[i].Score != diffArr[j].Score {
+			return diffArr[i].Score > diffArr[j].Score
+		}
+		return diffArr[i].Word < diffArr[j].Word
+	})
+
+	if len(diffArr) > n {
+		diffArr = diffArr[:n]
+	}
+	return diffArr
+}
+
+// количество всех учтенных слов.
+func (ws wordStats) total() int {
+	total := 0
+	for _, st := range ws {
+		total += st.count
+	}
+	return total
+}
+
+func frequency(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
+
+// сравниваем доли count/total без деления, чтобы равные доли не различались из-за округления.
+func diffStatus(countOld, totalOld, countNew, totalNew int) DiffStatus {
+	switch oldShare, newShare := countOld*totalNew, countNew*totalOld; {
+	case countOld == 0:
+		return DiffAppeared
+	case countNew == 0:
+		return DiffDisappeared
+	case newShare > oldShare:
+		return DiffIncreased
+	case newShare < oldShare:
+		return DiffDecreased
+	default:
+		return DiffUnchanged
+	}
+}
+
+// G² = 2 * Σ O * ln(O / E) по двум текстам: O - количество слова в тексте,
+// E - ожидаемое количество при одинаковой частоте в обоих текстах.
+func logLikelihood(countOld, totalOld, countNew, totalNew int) float64 {
+	all := float64(totalOld + totalNew)
+	count := float64(countOld + countNew)
+
+	g2 := 0.0
+	for _, o := range []struct{ count, total int }{{countOld, totalOld}, {countNew, totalNew}} {
+		if o.count > 0 {
+			expected := float64(o.total) * count / all
+			g2 += float64(o.count) * math.Log(float64(o.count)/expected)
+		}
+	}
+	return 2 * g2
+}
@@ -0,0 +1,73 @@
+package hw03frequencyanalysis
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	oldText := strings.Repeat("кот пес ", 10) + "кот кот мышь"
+	newText := strings.Repeat("кот пес ", 10) + "пес пес слон"
+
+	result := Diff(oldText, newText, 10)
+	require.Len(t, result, 4)
+
+	byWord := make(map[string]WordDiff, len(result))
+	for _, d := range result {
+		byWord[d.Word] = d
+	}
+	require.Equal(t, DiffAppeared, byWord["слон"].Status)
+	require.Equal(t, DiffDisappeared, byWord["мышь"].Status)
+	require.Equal(t, DiffIncreased, byWord["пес"].Status)
+	require.Equal(t, DiffDecreased, byWord["кот"].Status)
+
+	// кот: 12 из 23 слов -> 10 из 23. ожидаемое количество - 11 в каждом тексте
+	kot := byWord["кот"]
+	require.Equal(t, 12, kot.CountOld)
+	require.Equal(t, 10, kot.CountNew)
+	require.InDelta(t, 12.0/23, kot.FreqOld, 1e-9)
+	require.InDelta(t, 2*(12*math.Log(12.0/11)+10*math.Log(10.0/11)), kot.Score, 1e-9)
+
+	// по убыванию Score
+	for i := 1; i < len(result); i++ {
+		require.GreaterOrEqual(t, result[i-1].Score, result[i].Score)
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		result := Diff("a b", "a a b b", 10)
+		require.Equal(t, []WordDiff{
+			{Word: "a", Status: DiffUnchanged, CountOld: 1, CountNew: 2, FreqOld: 0.5, FreqNew: 0.5},
+			{Word: "b", Status: DiffUnchanged, CountOld: 1, CountNew: 2, FreqOld: 0.5, FreqNew: 0.5},
+		}, result)
+	})
+
+	t.Run("limit and empty", func(t *testing.T) {
+		require.Len(t, Diff(oldText, newText, 2), 2)
+		require.Len(t, Diff(oldText, newText, 0), 0)
+		require.Len(t, Diff("", "", 10), 0)
+
+		result := Diff("", "кот", 10)
+		require.Equal(t, []WordDiff{{Word: "кот", Status: DiffAppeared, CountNew: 1, FreqNew: 1}}, result)
+	})
+
+	t.Run("word forms", func(t *testing.T) {
+		result := Diff("нога ноги", "ногу ногу ногу рука", 10, WithNormalizer(Stemmer{}))
+		require.Len(t, result, 2)
+		require.Equal(t, "ногу", result[1].Word)
+		require.Equal(t, 2, result[1].CountOld)
+		require.Equal(t, 3, result[1].CountNew)
+		require.Equal(t, DiffDecreased, result[1].Status)
+	})
+
+	t.Run("counters", func(t *testing.T) {
+		oldCnt, newCnt := NewCounter(NewAnalyzer()), NewCounter(NewAnalyzer())
+		_, err := oldCnt.ReadFrom(strings.NewReader(oldText))
+		require.NoError(t, err)
+		_, err = newCnt.ReadFrom(strings.NewReader(newText))
+		require.NoError(t, err)
+		require.Equal(t, result, DiffCounters(oldCnt, newCnt, 10))
+	})
+}
@@ -0,0 +1,121 @@
+// Package cli - общие части команд wordfreq и freqdiff: запуск, флаги анализатора и чтение файлов.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	hw03frequencyanalysis "github.com/fixme_my_friend/hw03_frequency_analysis"
+)
+
+// коды завершения.
+const (
+	ExitOK    = 0
+	ExitUsage = 2 // ошибка параметров или ввода-вывода
+)
+
+// StdinArg - имя файла для чтения из stdin.
+const StdinArg = "-"
+
+var ErrStdinTwice = errors.New("stdin can be used for one file only")
+
+// Run разбирает аргументы функцией parse, выполняет команду функцией exec и возвращает код завершения.
+// ошибки выводятся в stderr, запрос справки (-h) ошибкой не считается.
+func Run[C any](stderr io.Writer, parse func() (C, error), exec func(cfg C) error) int {
+	cfg, err := parse()
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stderr, "Error: ", err)
+		}
+		return ExitUsage
+	}
+
+	if err := exec(cfg); err != nil {
+		fmt.Fprintln(stderr, "Error: ", err)
+		return ExitUsage
+	}
+	return ExitOK
+}
+
+// NewFlagSet создает набор флагов команды name, который выводит справку usage и ошибки в stderr.
+func NewFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// AnalyzerFlags - флаги параметров анализатора, общие для команд.
+type AnalyzerFlags struct {
+	Stem      bool
+	FoldCase  bool
+	MinLength int
+	StopWords string
+}
+
+// Register добавляет флаги в fs.
+func (f *AnalyzerFlags) Register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.Stem, "stem", false, "merge word forms with russian and english stemmers")
+	fs.BoolVar(&f.FoldCase, "fold-case", true, "ignore letter case")
+	fs.IntVar(&f.MinLength, "min-length", 0, "skip words shorter than this many letters")
+	fs.StringVar(&f.StopWords, "stop-words", "", "comma separated stop word lists to skip: ru, en")
+}
+
+// Analyzer создает анализатор по флагам.
+func (f *AnalyzerFlags) Analyzer() (*hw03frequencyanalysis.Analyzer, error) {
+	opts := []hw03frequencyanalysis.Option{
+		hw03frequencyanalysis.WithCaseFolding(f.FoldCase),
+		hw03frequencyanalysis.WithMinLength(f.MinLength),
+	}
+	if f.Stem {
+		opts = append(opts, hw03frequencyanalysis.WithNormalizer(hw03frequencyanalysis.Stemmer{}))
+	}
+	if f.StopWords != "" {
+		for _, lang := range strings.Split(f.StopWords, ",") {
+			words, ok := hw03frequencyanalysis.StopWords(strings.TrimSpace(lang))
+			if !ok {
+				return nil, fmt.Errorf("unknown stop word list %q", lang)
+			}
+			opts = append(opts, hw03frequencyanalysis.WithStopWords(words...))
+		}
+	}
+	return hw03frequencyanalysis.NewAnalyzer(opts...), nil
+}
+
+// CheckStdin возвращает ErrStdinTwice, если stdin указан среди файлов больше одного раза:
+// при повторном чтении он был бы пустым.
+func CheckStdin(files []string) error {
+	seen := false
+	for _, name := range files {
+		if name != StdinArg {
+			continue
+		}
+		if seen {
+			return ErrStdinTwice
+		}
+		seen = true
+	}
+	return nil
+}
+
+// ReadSource открывает файл name (для StdinArg - stdin) и передает его process.
+func ReadSource(name string, stdin io.Reader, process func(r io.Reader) error) error {
+	if name == StdinArg {
+		return process(stdin)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return process(f)
+}
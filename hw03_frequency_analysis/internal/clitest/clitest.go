@@ -0,0 +1,33 @@
+// Package clitest - вспомогательные функции для тестов команд. зависит только от стандартной библиотеки,
+// как и остальной код вне тестов.
+package clitest
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// RunFunc - функция запуска команды с аргументами и потоками ввода-вывода.
+type RunFunc func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+
+// Run запускает команду с текстом input в stdin и возвращает код завершения и вывод.
+func Run(t *testing.T, run RunFunc, input string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	outBuf, errBuf := bytes.Buffer{}, bytes.Buffer{}
+	code = run(args, strings.NewReader(input), &outBuf, &errBuf)
+	return code, outBuf.String(), errBuf.String()
+}
+
+// WriteFile создает в dir файл name с текстом text и возвращает путь к нему.
+func WriteFile(t *testing.T, dir, name, text string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,95 @@
+package hw03frequencyanalysis
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// разделитель слов в n-грамме.
+const ngramSeparator = " "
+
+// Collocation - устойчивое сочетание слов (n-грамма) с мерой связанности.
+type Collocation struct {
+	Phrase string  // слова n-граммы через пробел
+	Count  int     // количество вхождений n-граммы
+	PMI    float64 // поточечная взаимная информация (pointwise mutual information), бит
+}
+
+// TopNGrams возвращает n наиболее часто встречаемых n-грамм из size слов.
+func TopNGrams(text string, size, n int, opts ...Option) []WordCount {
+	return NewAnalyzer(opts...).TopNGrams(text, size, n)
+}
+
+// Collocations возвращает n сочетаний из size слов с наибольшей мерой PMI.
+func Collocations(text string, size, n, minCount int, opts ...Option) []Collocation {
+	return NewAnalyzer(opts...).Collocations(text, size, n, minCount)
+}
+
+// TopNGrams возвращает n наиболее часто встречаемых n-грамм из size слов (биграммы - size = 2,
+// триграммы - size = 3). слова разбираются по правилам анализатора, в WordCount.Word они записаны через пробел.
+// стоп-слова удаляются до составления n-грамм, поэтому n-грамма может объединять слова, не стоящие рядом.
+func (a *Analyzer) TopNGrams(text string, size, n int) []WordCount {
+	return a.rank(ngramStats(a.Words(text), size), n)
+}
+
+// Collocations возвращает n сочетаний из size слов, встречающихся вместе чаще, чем случайно.
+// мера - PMI = log2(P(w1..wk) / (P(w1) * ... * P(wk))). PMI завышена для редких сочетаний,
+// поэтому сочетания, встретившиеся реже minCount раз, не учитываются.
+// сочетания с одинаковой мерой упорядочиваются по количеству вхождений, затем лексикографически.
+func (a *Analyzer) Collocations(text string, size, n, minCount int) []Collocation {
+	words := a.Words(text)
+	grams := ngramStats(words, size)
+	if len(grams) == 0 || n <= 0 {
+		return []Collocation{}
+	}
+
+	wordCnt := make(map[string]int, len(words))
+	for _, w := range words {
+		wordCnt[w]++
+	}
+	totalWords := float64(len(words))
+	totalGrams := float64(len(words) - size + 1)
+
+	colArr := make([]Collocation, 0, len(grams))
+	for phrase, st := range grams {
+		if st.count < minCount {
+			continue
+		}
+
+		pmi := math.Log2(float64(st.count) / totalGrams)
+		for _, w := range strings.Split(phrase, ngramSeparator) {
+			pmi -= math.Log2(float64(wordCnt[w]) / totalWords)
+		}
+		colArr = append(colArr, Collocation{Phrase: phrase, Count: st.count, PMI: pmi})
+	}
+
+	sort.Slice(colArr, func(i, j int) bool {
+		switch {
+		case colArr[i].PMI != colArr[j].PMI:
+			return colArr[i].PMI > colArr[j].PMI
+		case colArr[i].Count != colArr[j].Count:
+			return colArr[i].Count > colArr[j].Count
+		default:
+			return colArr[i].Phrase < colArr[j].Phrase
+		}
+	})
+
+	if len(colArr) > n {
+		colArr = colArr[:n]
+	}
+	return colArr
+}
+
+// считаем n-граммы скользящим окном по словам. номер первого вхождения - позиция окна.
+func ngramStats(words []string, size int) wordStats {
+	if size <= 0 || len(words) < size {
+		return wordStats{}
+	}
+
+	stats := make(wordStats, len(words)-size+1)
+	for i := 0; i+size <= len(words); i++ {
+		stats.add(strings.Join(words[i:i+size], ngramSeparator), i)
+	}
+	return stats
+}
@@ -0,0 +1,74 @@
+package hw03frequencyanalysis
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopNGrams(t *testing.T) {
+	text := "Не могу войти в аккаунт. Не могу войти с телефона! Сброс пароля не приходит, не могу войти."
+
+	t.Run("bigrams", func(t *testing.T) {
+		expected := []WordCount{{"могу войти", 3}, {"не могу", 3}, {"аккаунт не", 1}}
+		require.Equal(t, expected, TopNGrams(text, 2, 3))
+	})
+
+	t.Run("trigrams", func(t *testing.T) {
+		expected := []WordCount{{"не могу войти", 3}, {"аккаунт не могу", 1}}
+		require.Equal(t, expected, TopNGrams(text, 3, 2))
+	})
+
+	t.Run("unigrams are TopN", func(t *testing.T) {
+		require.Equal(t, TopN(ruText, 10), TopNGrams(ruText, 1, 10))
+	})
+
+	t.Run("tie break", func(t *testing.T) {
+		expected := []WordCount{{"не могу", 3}, {"могу войти", 3}, {"войти в", 1}}
+		require.Equal(t, expected, TopNGrams(text, 2, 3, WithTieBreak(TieBreakFirstOccurrence)))
+	})
+
+	t.Run("stop words", func(t *testing.T) {
+		expected := []WordCount{{"могу войти", 3}, {"аккаунт могу", 1}}
+		require.Equal(t, expected, TopNGrams(text, 2, 2, WithStopWords(StopWordsRussian...)))
+	})
+
+	t.Run("not enough words", func(t *testing.T) {
+		require.Len(t, TopNGrams("один два", 3, 10), 0)
+		require.Len(t, TopNGrams("", 2, 10), 0)
+		require.Len(t, TopNGrams(text, 0, 10), 0)
+		require.Len(t, TopNGrams(text, -1, 10), 0)
+	})
+}
+
+func TestCollocations(t *testing.T) {
+	// "винни пух" всегда вместе, "и" встречается везде - связь слабая
+	text := strings.Repeat("винни пух и пятачок и сова и кролик ", 5) + "и я и ты"
+
+	result := Collocations(text, 2, 3, 2)
+	require.Len(t, result, 3)
+	require.Equal(t, "винни пух", result[0].Phrase)
+	require.Equal(t, 5, result[0].Count)
+
+	// PMI = log2(P(винни пух) / P(винни) / P(пух)): 5 биграмм из 43, по 5 слов из 44
+	expectedPMI := math.Log2(5.0/43) - 2*math.Log2(5.0/44)
+	require.InDelta(t, expectedPMI, result[0].PMI, 1e-9)
+
+	for i := 1; i < len(result); i++ {
+		require.GreaterOrEqual(t, result[i-1].PMI, result[i].PMI)
+	}
+
+	t.Run("min count", func(t *testing.T) {
+		for _, c := range Collocations(text, 2, 100, 5) {
+			require.GreaterOrEqual(t, c.Count, 5)
+		}
+		require.Len(t, Collocations(text, 2, 10, 100), 0)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.Len(t, Collocations("", 2, 10, 1), 0)
+		require.Len(t, Collocations(text, 2, 0, 1), 0)
+	})
+}
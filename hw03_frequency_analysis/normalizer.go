@@ -0,0 +1,34 @@
+package hw03frequencyanalysis
+
+import "unicode"
+
+// Normalizer приводит слово к основе. слова с одинаковой основой считаются одним словом.
+// Normalize вызывается из нескольких горутин (TopNParallel), поэтому реализация должна быть потокобезопасной.
+type Normalizer interface {
+	Normalize(word string) string
+}
+
+// NormalizerFunc позволяет использовать функцию как Normalizer.
+type NormalizerFunc func(word string) string
+
+// Normalize вызывает f(word).
+func (f NormalizerFunc) Normalize(word string) string {
+	return f(word)
+}
+
+// Stemmer - стеммер, выбирающий язык по первой букве слова: кириллица - RussianStemmer,
+// латиница - EnglishStemmer. остальные слова не изменяются.
+type Stemmer struct{}
+
+// Normalize возвращает основу слова в нижнем регистре.
+func (Stemmer) Normalize(word string) string {
+	for _, r := range word {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			return RussianStemmer{}.Normalize(word)
+		case unicode.Is(unicode.Latin, r):
+			return EnglishStemmer{}.Normalize(word)
+		}
+	}
+	return word
+}
@@ -0,0 +1,135 @@
+package hw03frequencyanalysis
+
+import (
+	"runtime"
+	"sync"
+	"unicode/utf8"
+)
+
+// количество частей текста на одного обработчика. части меньше - нагрузка между обработчиками ровнее.
+const chunksPerWorker = 4
+
+// часть текста для обработки. offset - смещение части в исходном тексте в байтах.
+type chunk struct {
+	text   string
+	offset int
+}
+
+// TopNParallel - аналог TopN, считающий слова в нескольких горутинах.
+// workers <= 0 - по количеству процессоров (GOMAXPROCS). результат совпадает с TopN.
+func TopNParallel(text string, n, workers int, opts ...Option) []WordCount {
+	return NewAnalyzer(opts...).TopNParallel(text, n, workers)
+}
+
+// TopNParallel - аналог Analyzer.TopN, считающий слова в нескольких горутинах.
+// текст делится на части по границам слов, каждый обработчик считает слова в свой словарь,
+// в конце словари объединяются. workers <= 0 - по количеству процессоров (GOMAXPROCS).
+func (a *Analyzer) TopNParallel(text string, n, workers int) []WordCount {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	return a.rank(a.countParallel(text, workers), n)
+}
+
+// подсчет по модели hw05 Run: части текста передаются через канал n обработчикам.
+// номер первого вхождения слова - смещение в байтах: оно сравнимо между частями
+// и дает тот же порядок, что и номер слова при последовательном подсчете.
+func (a *Analyzer) countParallel(text string, workers int) wordStats {
+	chunks := splitChunks(text, workers*chunksPerWorker, a.isSeparator)
+
+	ch := make(chan chunk, workers)
+	workerStats := make([]wordStats, workers)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		workerStats[i] = make(wordStats)
+		go func(ws wordStats) {
+			defer wg.Done()
+			for c := range ch {
+				a.countChunk(c, ws)
+			}
+		}(workerStats[i])
+	}
+
+	for _, c := range chunks {
+		ch <- c
+	}
+
+	close(ch)
+	wg.Wait()
+
+	return mergeStats(workerStats)
+}
+
+// считаем слова части текста в словарь ws.
+func (a *Analyzer) countChunk(c chunk, ws wordStats) {
+	wordStart := -1
+	for i, r := range c.text {
+		switch sep := a.isSeparator(r); {
+		case sep && wordStart >= 0:
+			a.countWord(ws, c.text[wordStart:i], c.offset+wordStart)
+			wordStart = -1
+		case !sep && wordStart < 0:
+			wordStart = i
+		}
+	}
+
+	if wordStart >= 0 {
+		a.countWord(ws, c.text[wordStart:], c.offset+wordStart)
+	}
+}
+
+func (a *Analyzer) countWord(ws wordStats, field string, pos int) {
+	if w, ok := a.normalize(field); ok {
+		a.addWord(ws.add, w, pos)
+	}
+}
+
+// объединяем словари обработчиков: количества и словоформы складываем, первое вхождение - минимальное.
+func mergeStats(statsArr []wordStats) wordStats {
+	merged := statsArr[0]
+	for _, ws := range statsArr[1:] {
+		for word, st := range ws {
+			m, ok := merged[word]
+			if !ok {
+				merged[word] = st
+				continue
+			}
+			m.merge(st)
+		}
+	}
+	return merged
+}
+
+// делим текст примерно на parts частей. граница части сдвигается до ближайшего разделителя,
+// поэтому слова не разрываются.
+func splitChunks(text string, parts int, isSeparator func(r rune) bool) []chunk {
+	size := len(text)/parts + 1
+	chunks := make([]chunk, 0, parts)
+
+	for start := 0; start < len(text); {
+		end := start + size
+		if end > len(text) {
+			end = len(text)
+		}
+
+		// сдвигаем границу на начало руны, затем - до разделителя
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+		for end < len(text) {
+			r, width := utf8.DecodeRuneInString(text[end:])
+			if isSeparator(r) {
+				break
+			}
+			end += width
+		}
+
+		chunks = append(chunks, chunk{text: text[start:end], offset: start})
+		start = end
+	}
+
+	return chunks
+}
@@ -0,0 +1,83 @@
+package hw03frequencyanalysis
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopNParallel(t *testing.T) {
+	bigText := strings.Repeat(ruText+enText+sanskritTest, 20)
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "default options", opts: nil},
+		{name: "first occurrence", opts: []Option{WithTieBreak(TieBreakFirstOccurrence)}},
+		{name: "collation", opts: []Option{WithTieBreak(TieBreakCollation), WithCaseFolding(false)}},
+		{name: "unicode split", opts: []Option{WithUnicodeSplit("-"), WithStopWords(StopWordsEnglish...)}},
+		{name: "stemming", opts: []Option{WithNormalizer(Stemmer{}), WithTieBreak(TieBreakFirstOccurrence)}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			for _, text := range []string{"", "  ", "word", ruText, enText, sanskritTest, bigText} {
+				expected := TopN(text, 50, tc.opts...)
+				for _, workers := range []int{-1, 0, 1, 2, 3, 8, 100} {
+					require.Equal(t, expected, TopNParallel(text, 50, workers, tc.opts...), "workers %d", workers)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitChunks(t *testing.T) {
+	text := "Нога, нога! какой-то\tкакойто日本語 語"
+	a := NewAnalyzer()
+
+	for parts := 1; parts <= len(text)+1; parts++ {
+		chunks := splitChunks(text, parts, a.isSeparator)
+
+		joined := strings.Builder{}
+		for _, c := range chunks {
+			require.Equal(t, joined.Len(), c.offset)
+			joined.WriteString(c.text)
+		}
+		require.Equal(t, text, joined.String(), "parts %d", parts)
+
+		// сумма слов частей равна словам всего текста
+		words := 0
+		for _, c := range chunks {
+			words += len(a.Words(c.text))
+		}
+		require.Equal(t, len(a.Words(text)), words, "parts %d", parts)
+	}
+}
+
+var benchText = strings.Repeat(ruText+enText+sanskritTest, 2000)
+
+func BenchmarkTopN(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		TopN(benchText, 10)
+	}
+}
+
+func BenchmarkTop10(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Top10(benchText)
+	}
+}
+
+func BenchmarkTopNParallel(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(strconv.Itoa(workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				TopNParallel(benchText, 10, workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package hw03frequencyanalysis
+
+import "container/heap"
+
+// spaceSaving - приближенный подсчет частых слов алгоритмом Space-Saving
+// (Metwally, Agrawal, El Abbadi, 2005). хранит не более capacity счетчиков.
+// если слова нет среди счетчиков и места нет - оно замещает слово с минимальным счетчиком,
+// наследуя его значение. унаследованная часть - это погрешность оценки (не больше N/capacity).
+type spaceSaving struct {
+	capacity int
+	items    map[string]*ssCounter
+	minHeap  ssHeap // счетчики, упорядоченные по возрастанию count
+}
+
+type ssCounter struct {
+	word     string
+	wordStat     // count - оценка количества (сверху), first - номер вхождения, с которого слово отслеживается
+	err      int // максимальное завышение оценки
+	index    int // позиция в куче
+}
+
+func newSpaceSaving(capacity int) *spaceSaving {
+	return &spaceSaving{
+		capacity: capacity,
+		items:    make(map[string]*ssCounter, capacity),
+		minHeap:  make(ssHeap, 0, capacity),
+	}
+}
+
+func (s *spaceSaving) add(word string, pos int) *wordStat {
+	// слово отслеживается - увеличиваем счетчик
+	if c, ok := s.items[word]; ok {
+		c.count++
+		heap.Fix(&s.minHeap, c.index)
+		return &c.wordStat
+	}
+
+	// есть свободное место - добавляем новый счетчик
+	if len(s.minHeap) < s.capacity {
+		c := &ssCounter{word: word, wordStat: wordStat{count: 1, first: pos}}
+		s.items[word] = c
+		heap.Push(&s.minHeap, c)
+		return &c.wordStat
+	}
+
+	// замещаем слово с минимальным счетчиком. словоформы вытесненного слова не наследуются
+	c := s.minHeap[0]
+	delete(s.items, c.word)
+	c.word, c.err, c.first, c.forms = word, c.count, pos, nil
+	c.count++
+	s.items[word] = c
+	heap.Fix(&s.minHeap, 0)
+	return &c.wordStat
+}
+
+// статистика по отслеживаемым словам для ранжирования.
+func (s *spaceSaving) stats() wordStats {
+	ws := make(wordStats, len(s.items))
+	for word, c := range s.items {
+		st := c.wordStat
+		ws[word] = &st
+	}
+	return ws
+}
+
+// максимальная погрешность среди отслеживаемых слов.
+func (s *spaceSaving) errorBound() int {
+	bound := 0
+	for _, c := range s.minHeap {
+		if c.err > bound {
+			bound = c.err
+		}
+	}
+	return bound
+}
+
+// ssHeap - min-куча счетчиков для container/heap.
+type ssHeap []*ssCounter
+
+func (h ssHeap) Len() int { return len(h) }
+
+func (h ssHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+
+func (h ssHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *ssHeap) Push(x interface{}) {
+	c := x.(*ssCounter)
+	c.index = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *ssHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return c
+}
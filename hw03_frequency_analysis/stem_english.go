@@ -0,0 +1,261 @@
+package hw03frequencyanalysis
+
+import (
+	"bytes"
+	"strings"
+)
+
+// EnglishStemmer - стеммер английского языка по алгоритму Snowball Porter2
+// (https://snowballstem.org/algorithms/english/stemmer.html).
+type EnglishStemmer struct{}
+
+var (
+	// слова-исключения и их основы.
+	enExceptions = map[string]string{
+		"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+		"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli", "singly": "singl",
+		"sky": "sky", "news": "news", "howe": "howe", "atlas": "atlas", "cosmos": "cosmos", "bias": "bias", "andes": "andes",
+	}
+	// слова, которые не изменяются после шага 1a.
+	enInvariantAfter1a = map[string]struct{}{
+		"inning": {}, "outing": {}, "canning": {}, "herring": {}, "earring": {},
+		"proceed": {}, "exceed": {}, "succeed": {},
+	}
+	// приставки, после которых начинается R1.
+	enR1Prefixes = []string{"gener", "commun", "arsen"}
+
+	enStep2Suffixes = map[string]string{
+		"tional": "tion", "enci": "ence", "anci": "ance", "abli": "able", "entli": "ent",
+		"izer": "ize", "ization": "ize", "ational": "ate", "ation": "ate", "ator": "ate",
+		"alism": "al", "aliti": "al", "alli": "al", "fulness": "ful", "ousli": "ous", "ousness": "ous",
+		"iveness": "ive", "iviti": "ive", "biliti": "ble", "bli": "ble", "ogi": "og", "fulli": "ful",
+		"lessli": "less", "li": "",
+	}
+	enStep3Suffixes = map[string]string{
+		"tional": "tion", "ational": "ate", "alize": "al", "icate": "ic", "iciti": "ic", "ical": "ic",
+		"ful": "", "ness": "", "ative": "",
+	}
+	enStep4Suffixes = []string{
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment", "ent",
+		"ism", "ate", "iti", "ous", "ive", "ize", "ion",
+	}
+)
+
+// слово в процессе стемминга. r1, r2 - начала областей R1 и R2 исходного слова.
+type enWord struct {
+	b      []byte
+	r1, r2 int
+}
+
+// Normalize возвращает основу слова в нижнем регистре.
+func (EnglishStemmer) Normalize(word string) string {
+	w := strings.ToLower(word)
+	if stem, ok := enExceptions[w]; ok {
+		return stem
+	}
+	if len(w) < 3 {
+		return w
+	}
+
+	// y в начале слова и после гласной - согласная, обозначаем ее Y
+	b := []byte(strings.TrimPrefix(w, "'"))
+	for i := range b {
+		if b[i] == 'y' && (i == 0 || isEnVowel(b[i-1])) {
+			b[i] = 'Y'
+		}
+	}
+
+	ew := &enWord{b: b}
+	ew.markRegions()
+	ew.step0()
+	ew.step1a()
+	if _, ok := enInvariantAfter1a[string(ew.b)]; !ok {
+		ew.step1b()
+		ew.step1c()
+		ew.step2()
+		ew.step3()
+		ew.step4()
+		ew.step5()
+	}
+
+	return strings.ReplaceAll(string(ew.b), "Y", "y")
+}
+
+func (w *enWord) markRegions() {
+	w.r1 = afterVowelConsonant(w.b, 0, isEnVowel)
+	for _, p := range enR1Prefixes {
+		if bytes.HasPrefix(w.b, []byte(p)) {
+			w.r1 = len(p)
+			break
+		}
+	}
+	w.r2 = afterVowelConsonant(w.b, w.r1, isEnVowel)
+}
+
+// самый длинный из суффиксов, которым оканчивается слово. нет такого - "".
+func (w *enWord) suffix(suffixes ...string) string {
+	longest := ""
+	for _, s := range suffixes {
+		if len(s) > len(longest) && bytes.HasSuffix(w.b, []byte(s)) {
+			longest = s
+		}
+	}
+	return longest
+}
+
+// самый длинный из суффиксов-ключей m, которым оканчивается слово. нет такого - "".
+func (w *enWord) mappedSuffix(m map[string]string) string {
+	longest := ""
+	for s := range m {
+		if len(s) > len(longest) && bytes.HasSuffix(w.b, []byte(s)) {
+			longest = s
+		}
+	}
+	return longest
+}
+
+// заменяем суффикс suffix на repl.
+func (w *enWord) replace(suffix, repl string) {
+	w.b = append(w.b[:len(w.b)-len(suffix)], repl...)
+}
+
+// суффикс целиком лежит в области, начинающейся с region.
+func (w *enWord) in(region int, suffix string) bool {
+	return len(w.b)-len(suffix) >= region
+}
+
+// буква перед суффиксом.
+func (w *enWord) before(suffix string) byte {
+	if i := len(w.b) - len(suffix) - 1; i >= 0 {
+		return w.b[i]
+	}
+	return 0
+}
+
+// часть слова b[:end] оканчивается коротким слогом: согласная-гласная-согласная (кроме w, x, Y)
+// или гласная-согласная в начале слова.
+func (w *enWord) endsShortSyllable(end int) bool {
+	b := w.b[:end]
+	n := len(b)
+	if n >= 3 {
+		return !isEnVowel(b[n-3]) && isEnVowel(b[n-2]) && !isEnVowel(b[n-1]) && strings.IndexByte("wxY", b[n-1]) < 0
+	}
+	return n == 2 && isEnVowel(b[0]) && !isEnVowel(b[1])
+}
+
+// шаг 0: притяжательные окончания.
+func (w *enWord) step0() {
+	if s := w.suffix("'s'", "'s", "'"); s != "" {
+		w.replace(s, "")
+	}
+}
+
+// шаг 1a: множественное число.
+func (w *enWord) step1a() {
+	switch s := w.suffix("sses", "ied", "ies", "s", "us", "ss"); s {
+	case "sses":
+		w.replace(s, "ss")
+	case "ied", "ies":
+		if len(w.b)-len(s) > 1 {
+			w.replace(s, "i")
+		} else {
+			w.replace(s, "ie")
+		}
+	case "s":
+		// s удаляется, если перед предыдущей буквой есть гласная
+		if len(w.b) >= 2 && bytes.ContainsAny(w.b[:len(w.b)-2], "aeiouy") {
+			w.replace(s, "")
+		}
+	}
+}
+
+// шаг 1b: прошедшее время и -ing.
+func (w *enWord) step1b() {
+	switch s := w.suffix("eed", "eedly", "ed", "edly", "ing", "ingly"); s {
+	case "":
+	case "eed", "eedly":
+		if w.in(w.r1, s) {
+			w.replace(s, "ee")
+		}
+	default:
+		if !bytes.ContainsAny(w.b[:len(w.b)-len(s)], "aeiouy") {
+			return
+		}
+		w.replace(s, "")
+
+		switch n := len(w.b); {
+		case w.suffix("at", "bl", "iz") != "":
+			w.b = append(w.b, 'e')
+		case w.suffix("bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt") != "":
+			w.b = w.b[:n-1]
+		case n == w.r1 && w.endsShortSyllable(n):
+			w.b = append(w.b, 'e')
+		}
+	}
+}
+
+// шаг 1c: y после согласной (не первой буквы слова) заменяется на i.
+func (w *enWord) step1c() {
+	if n := len(w.b); n > 2 && (w.b[n-1] == 'y' || w.b[n-1] == 'Y') && !isEnVowel(w.b[n-2]) {
+		w.b[n-1] = 'i'
+	}
+}
+
+// шаг 2: суффиксы в R1.
+func (w *enWord) step2() {
+	s := w.mappedSuffix(enStep2Suffixes)
+	if s == "" || !w.in(w.r1, s) {
+		return
+	}
+
+	switch s {
+	case "ogi":
+		if w.before(s) != 'l' {
+			return
+		}
+	case "li":
+		if w.before(s) == 0 || strings.IndexByte("cdeghkmnrt", w.before(s)) < 0 {
+			return
+		}
+	}
+	w.replace(s, enStep2Suffixes[s])
+}
+
+// шаг 3: суффиксы в R1, ative - в R2.
+func (w *enWord) step3() {
+	s := w.mappedSuffix(enStep3Suffixes)
+	if s == "" || !w.in(w.r1, s) || (s == "ative" && !w.in(w.r2, s)) {
+		return
+	}
+	w.replace(s, enStep3Suffixes[s])
+}
+
+// шаг 4: суффиксы в R2, ion - после s или t.
+func (w *enWord) step4() {
+	s := w.suffix(enStep4Suffixes...)
+	if s == "" || !w.in(w.r2, s) {
+		return
+	}
+	if s == "ion" && w.before(s) != 's' && w.before(s) != 't' {
+		return
+	}
+	w.replace(s, "")
+}
+
+// шаг 5: конечные e и l.
+func (w *enWord) step5() {
+	switch s := w.suffix("e", "l"); s {
+	case "e":
+		if w.in(w.r2, s) || (w.in(w.r1, s) && !w.endsShortSyllable(len(w.b)-1)) {
+			w.replace(s, "")
+		}
+	case "l":
+		if w.in(w.r2, s) && w.before(s) == 'l' {
+			w.replace(s, "")
+		}
+	}
+}
+
+func isEnVowel(b byte) bool {
+	return strings.IndexByte("aeiouy", b) >= 0
+}
@@ -0,0 +1,152 @@
+package hw03frequencyanalysis
+
+import (
+	"sort"
+	"strings"
+)
+
+// RussianStemmer - стеммер русского языка по алгоритму Snowball
+// (https://snowballstem.org/algorithms/russian/stemmer.html). ё считается е.
+type RussianStemmer struct{}
+
+// окончание и признак группы 1: окончание должно следовать за а или я.
+type ruEnding struct {
+	suffix   []rune
+	afterAYa bool
+}
+
+// окончания одного класса, упорядоченные по убыванию длины.
+type ruEndings []ruEnding
+
+func newRuEndings(afterAYa, other []string) ruEndings {
+	endings := make(ruEndings, 0, len(afterAYa)+len(other))
+	for _, s := range afterAYa {
+		endings = append(endings, ruEnding{suffix: []rune(s), afterAYa: true})
+	}
+	for _, s := range other {
+		endings = append(endings, ruEnding{suffix: []rune(s)})
+	}
+	sort.SliceStable(endings, func(i, j int) bool {
+		return len(endings[i].suffix) > len(endings[j].suffix)
+	})
+	return endings
+}
+
+var (
+	ruPerfectiveGerund = newRuEndings(
+		[]string{"в", "вши", "вшись"},
+		[]string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"},
+	)
+	ruAdjective = newRuEndings(nil, []string{
+		"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом",
+		"его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею",
+	})
+	ruParticiple = newRuEndings(
+		[]string{"ем", "нн", "вш", "ющ", "щ"},
+		[]string{"ивш", "ывш", "ующ"},
+	)
+	ruReflexive = newRuEndings(nil, []string{"ся", "сь"})
+	ruVerb      = newRuEndings(
+		[]string{"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет", "ют", "ны", "ть", "ешь", "нно"},
+		[]string{
+			"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй", "ил", "ыл", "им", "ым", "ен",
+			"ило", "ыло", "ено", "ят", "ует", "уют", "ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю",
+		},
+	)
+	ruNoun = newRuEndings(nil, []string{
+		"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и", "ией", "ей", "ой", "ий", "й",
+		"иям", "ям", "ием", "ем", "ам", "ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я",
+	})
+	ruSuperlative  = newRuEndings(nil, []string{"ейш", "ейше"})
+	ruDerivational = newRuEndings(nil, []string{"ост", "ость"})
+)
+
+// удаляем самое длинное окончание, целиком лежащее в области w[limit:].
+// как в Snowball, если для самого длинного окончания не выполнено условие группы 1, более короткие не проверяются.
+func (e ruEndings) remove(w []rune, limit int) ([]rune, bool) {
+	for _, end := range e {
+		start := len(w) - len(end.suffix)
+		if start < limit || !hasRuneSuffix(w, end.suffix) {
+			continue
+		}
+		if end.afterAYa && (start-1 < limit || (w[start-1] != 'а' && w[start-1] != 'я')) {
+			return w, false
+		}
+		return w[:start], true
+	}
+	return w, false
+}
+
+// Normalize возвращает основу слова в нижнем регистре.
+func (RussianStemmer) Normalize(word string) string {
+	w := []rune(strings.ReplaceAll(strings.ToLower(word), "ё", "е"))
+	rv, r2 := ruRegions(w)
+
+	// шаг 1: деепричастие, иначе возвратная частица и прилагательное/причастие, глагол или существительное
+	var ok bool
+	if w, ok = ruPerfectiveGerund.remove(w, rv); !ok {
+		w, _ = ruReflexive.remove(w, rv)
+		if w, ok = ruAdjective.remove(w, rv); ok {
+			w, _ = ruParticiple.remove(w, rv)
+		} else if w, ok = ruVerb.remove(w, rv); !ok {
+			w, _ = ruNoun.remove(w, rv)
+		}
+	}
+
+	// шаг 2: и на конце
+	if n := len(w); n > rv && w[n-1] == 'и' {
+		w = w[:n-1]
+	}
+
+	// шаг 3: словообразовательный суффикс в R2
+	w, _ = ruDerivational.remove(w, r2)
+
+	// шаг 4: превосходная степень и двойная н, либо мягкий знак
+	w, ok = ruSuperlative.remove(w, rv)
+	if n := len(w); n-2 >= rv && w[n-1] == 'н' && w[n-2] == 'н' {
+		w = w[:n-1]
+	} else if !ok && n > rv && w[n-1] == 'ь' {
+		w = w[:n-1]
+	}
+
+	return string(w)
+}
+
+// области слова по Snowball: RV - после первой гласной, R2 - вторая область R1 (после сочетания гласная-согласная).
+func ruRegions(w []rune) (rv, r2 int) {
+	rv = len(w)
+	for i, r := range w {
+		if isRuVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+	r1 := afterVowelConsonant(w, 0, isRuVowel)
+	return rv, afterVowelConsonant(w, r1, isRuVowel)
+}
+
+// позиция после первой согласной, следующей за гласной, начиная с start. нет такой - длина слова.
+func afterVowelConsonant[T rune | byte](w []T, start int, isVowel func(T) bool) int {
+	for i := start + 1; i < len(w); i++ {
+		if isVowel(w[i-1]) && !isVowel(w[i]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+func isRuVowel(r rune) bool {
+	return strings.ContainsRune("аеиоуыэюя", r)
+}
+
+func hasRuneSuffix(w, suffix []rune) bool {
+	if len(suffix) > len(w) {
+		return false
+	}
+	for i, r := range suffix {
+		if w[len(w)-len(suffix)+i] != r {
+			return false
+		}
+	}
+	return true
+}
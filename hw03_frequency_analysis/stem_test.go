@@ -0,0 +1,46 @@
+package hw03frequencyanalysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRussianStemmer(t *testing.T) {
+	// примеры из словаря Snowball
+	tests := map[string]string{
+		"нога": "ног", "ногу": "ног", "ноги": "ног", "ногой": "ног",
+		"вагон": "вагон", "вагона": "вагон", "вагонов": "вагон", "вагоны": "вагон",
+		"важная": "важн", "важнее": "важн", "важнейшие": "важн", "важного": "важн", "важными": "важн",
+		"важности": "важност", "важностью": "важност", "важничал": "важнича",
+		"валерия": "валер", "валетами": "валет", "валился": "вал",
+		"сосредоточиться": "сосредоточ", "вероятно": "вероятн", "подходящее": "подходя",
+		"ёлки": "елк", "Кристофер": "кристофер", "я": "я", "": "",
+	}
+	for word, stem := range tests {
+		require.Equal(t, stem, RussianStemmer{}.Normalize(word), word)
+	}
+}
+
+func TestEnglishStemmer(t *testing.T) {
+	// примеры из словаря Snowball
+	tests := map[string]string{
+		"consign": "consign", "consigned": "consign", "consigning": "consign", "consignment": "consign",
+		"consistency": "consist", "consistently": "consist", "consolation": "consol", "consolatory": "consolatori",
+		"conspiracy": "conspiraci", "conspirators": "conspir", "constable": "constabl", "constancy": "constanc",
+		"knackeries": "knackeri", "kneaded": "knead", "kneeling": "kneel", "knees": "knee", "knightly": "knight",
+		"knitting": "knit", "knives": "knive", "hoping": "hope", "cries": "cri", "ties": "tie", "gas": "gas",
+		"gaps": "gap", "generously": "generous", "generate": "generat", "skies": "sky", "Dying": "die",
+		"succeeding": "succeed", "enjoying": "enjoy", "happily": "happili", "dog's": "dog", "is": "is",
+	}
+	for word, stem := range tests {
+		require.Equal(t, stem, EnglishStemmer{}.Normalize(word), word)
+	}
+}
+
+func TestStemmer(t *testing.T) {
+	require.Equal(t, "ног", Stemmer{}.Normalize("Ноги"))
+	require.Equal(t, "knit", Stemmer{}.Normalize("knitting"))
+	require.Equal(t, "日本語", Stemmer{}.Normalize("日本語"))
+	require.Equal(t, "42", Stemmer{}.Normalize("42"))
+}
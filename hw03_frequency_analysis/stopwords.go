@@ -0,0 +1,40 @@
+package hw03frequencyanalysis
+
+// списки стоп-слов (служебные слова, не несущие смысла для частотного анализа).
+// основаны на списках проекта Snowball. используются как WithStopWords(StopWordsRussian...).
+
+var StopWordsRussian = []string{
+	"а", "без", "более", "бы", "был", "была", "были", "было", "быть", "в", "вам", "вас", "весь", "во",
+	"вот", "все", "всего", "всех", "вы", "где", "да", "даже", "для", "до", "его", "ее", "её", "ей", "ему",
+	"если", "есть", "еще", "ещё", "же", "за", "здесь", "и", "из", "или", "им", "их", "к", "как", "какая",
+	"какой", "когда", "кто", "ли", "либо", "мне", "может", "мы", "на", "над", "надо", "наш", "не", "него",
+	"нее", "неё", "нет", "ни", "них", "но", "ну", "о", "об", "однако", "он", "она", "они", "оно", "от",
+	"очень", "по", "под", "при", "с", "со", "так", "также", "такой", "там", "те", "тем", "то", "того",
+	"тоже", "той", "только", "том", "ты", "у", "уже", "хотя", "чего", "чей", "чем", "что", "чтобы", "чье",
+	"чья", "эта", "эти", "это", "я",
+}
+
+var StopWordsEnglish = []string{
+	"a", "about", "above", "after", "again", "against", "all", "am", "an", "and", "any", "are", "as", "at",
+	"be", "because", "been", "before", "being", "below", "between", "both", "but", "by", "can", "did", "do",
+	"does", "doing", "down", "during", "each", "few", "for", "from", "further", "had", "has", "have",
+	"having", "he", "her", "here", "hers", "herself", "him", "himself", "his", "how", "i", "if", "in",
+	"into", "is", "it", "its", "itself", "just", "me", "more", "most", "my", "myself", "no", "nor", "not",
+	"now", "of", "off", "on", "once", "only", "or", "other", "our", "ours", "ourselves", "out", "over",
+	"own", "same", "she", "should", "so", "some", "such", "than", "that", "the", "their", "theirs", "them",
+	"themselves", "then", "there", "these", "they", "this", "those", "through", "to", "too", "under",
+	"until", "up", "very", "was", "we", "were", "what", "when", "where", "which", "while", "who", "whom",
+	"why", "will", "with", "you", "your", "yours", "yourself", "yourselves",
+}
+
+// мапинг кодов языков на списки стоп-слов.
+var stopWordLists = map[string][]string{
+	"ru": StopWordsRussian,
+	"en": StopWordsEnglish,
+}
+
+// StopWords возвращает список стоп-слов языка по коду: "ru" или "en". false - списка для языка нет.
+func StopWords(lang string) ([]string, bool) {
+	words, ok := stopWordLists[lang]
+	return words, ok
+}
@@ -0,0 +1,64 @@
+package hw03frequencyanalysis
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TieBreak - порядок слов с одинаковой частотой.
+type TieBreak uint
+
+const (
+	// TieBreakLexicographic - лексикографический порядок (по байтам utf-8), как в Top10.
+	TieBreakLexicographic TieBreak = iota
+	// TieBreakFirstOccurrence - в порядке первого вхождения слова в текст.
+	TieBreakFirstOccurrence
+	// TieBreakCollation - по алфавиту с учетом правил языка (см. WithCollator).
+	TieBreakCollation
+)
+
+// Collator сравнивает строки по правилам языка. возвращает -1, 0 или 1 как strings.Compare.
+// ему удовлетворяет *collate.Collator из golang.org/x/text/collate.
+type Collator interface {
+	CompareString(a, b string) int
+}
+
+// WithTieBreak задает порядок слов с одинаковой частотой.
+func WithTieBreak(tb TieBreak) Option {
+	return func(a *Analyzer) {
+		a.tieBreak = tb
+	}
+}
+
+// WithCollator задает сравнение слов для TieBreakCollation и включает этот порядок.
+func WithCollator(c Collator) Option {
+	return func(a *Analyzer) {
+		a.collator = c
+		a.tieBreak = TieBreakCollation
+	}
+}
+
+// BasicCollator - простая алфавитная сортировка для русского и английского текста.
+// в отличие от лексикографической: "ё" стоит рядом с "е", а не после "я",
+// регистр и знаки препинания ("какой-то") учитываются только при совпадении остальных букв.
+type BasicCollator struct{}
+
+func (BasicCollator) CompareString(a, b string) int {
+	if c := strings.Compare(collationKey(a), collationKey(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// ключ сравнения: буквы в нижнем регистре без знаков препинания, "ё" заменяется на "е".
+func collationKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r = unicode.ToLower(r); {
+		case r == 'ё':
+			return 'е'
+		case unicode.IsPunct(r):
+			return -1
+		}
+		return r
+	}, s)
+}
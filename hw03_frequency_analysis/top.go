@@ -1,64 +1,127 @@
 package hw03frequencyanalysis
 
 import (
-	"regexp"
 	"sort"
-	"strings"
 )
 
-// структура для хранеиения слов и их количества в итоговом слайсе.
-type words struct {
-	word  string
+// WordCount - слово и количество его вхождений в текст.
+type WordCount struct {
+	Word  string
+	Count int
+}
+
+// статистика по слову при подсчете.
+type wordStat struct {
 	count int
+	first int            // номер первого вхождения слова в тексте
+	forms map[string]int // словоформы и их количество, если слова приводятся к основе (WithNormalizer)
 }
 
-// регулярка со знаками припенания.
-// "–" в конце - это тире, а не дефис. дефис обрабатывается отдельно.
-var rePunctSymbl = regexp.MustCompile(`[.,!?'"()\\/\[\]{}–]+`)
+// учитываем словоформу слова.
+func (st *wordStat) addForm(form string) {
+	if st.forms == nil {
+		st.forms = make(map[string]int, 1)
+	}
+	st.forms[form]++
+}
+
+// добавляем статистику other: количества и словоформы складываем, первое вхождение - минимальное.
+func (st *wordStat) merge(other *wordStat) {
+	st.count += other.count
+	if other.first < st.first {
+		st.first = other.first
+	}
+	for form, cnt := range other.forms {
+		if st.forms == nil {
+			st.forms = make(map[string]int, len(other.forms))
+		}
+		st.forms[form] += cnt
+	}
+}
+
+// представитель слова в результате - самая частая словоформа (при равенстве - меньшая лексикографически).
+// если словоформы не учитывались - само слово.
+func (st *wordStat) representative(word string) string {
+	best, bestCnt := word, 0
+	for form, cnt := range st.forms {
+		if cnt > bestCnt || (cnt == bestCnt && form < best) {
+			best, bestCnt = form, cnt
+		}
+	}
+	return best
+}
+
+// словарь статистики по словам.
+type wordStats map[string]*wordStat
+
+// учитываем очередное вхождение слова. pos - номер вхождения в тексте.
+func (ws wordStats) add(word string, pos int) *wordStat {
+	if st, ok := ws[word]; ok {
+		st.count++
+		return st
+	}
+	st := &wordStat{count: 1, first: pos}
+	ws[word] = st
+	return st
+}
+
+// анализатор с настройками по умолчанию для Top10.
+var defaultAnalyzer = NewAnalyzer()
 
 // функция возвращающает слайс с 10-ю наиболее часто встречаемыми во входном тексте словами.
 func Top10(inStr string) []string {
-	outArr := make([]string, 0, 10)
+	return defaultAnalyzer.Top(inStr, 10)
+}
 
-	// удаляем из текста знаки припенания (меняем их на пробел).
-	inStr = rePunctSymbl.ReplaceAllString(strings.ToLower(inStr), " ")
-	// переводим строку в слайс слов по пробелам
-	sArr := strings.Fields(inStr)
+// TopN возвращает n наиболее часто встречаемых слов с количеством вхождений.
+// без параметров слова разбираются как в Top10, слова с одинаковой частотой сортируются лексикографически.
+func TopN(text string, n int, opts ...Option) []WordCount {
+	return NewAnalyzer(opts...).TopN(text, n)
+}
 
-	if len(sArr) == 0 {
-		return outArr
+// функция возвращает n наиболее часто встречаемых слов из посчитанного словаря.
+func (a *Analyzer) rank(stats wordStats, n int) []WordCount {
+	if n < 0 {
+		n = 0
 	}
 
-	// считаем слова через словарь.
-	mapStr := make(map[string]int, len(sArr))
-	for _, s := range sArr {
-		if _, ok := mapStr[s]; ok {
-			mapStr[s]++
-		} else if s != "-" { // если слово дефис - пропускаем
-			mapStr[s] = 1
-		}
+	// переводим посчитанный словарь в слайс для сортировки.
+	type rankedWord struct {
+		WordCount
+		first int
 	}
-
-	// переводим посчитанныц словарь в слайс объектов words.
-	wrdsArr := make([]words, 0, len(mapStr))
-	for str, cnt := range mapStr {
-		wrdsArr = append(wrdsArr, words{word: str, count: cnt})
+	rwArr := make([]rankedWord, 0, len(stats))
+	for word, st := range stats {
+		rwArr = append(rwArr, rankedWord{WordCount{Word: st.representative(word), Count: st.count}, st.first})
 	}
 
-	// сортируем лексикографически по словам.
-	sort.Slice(wrdsArr, func(i, j int) bool { return wrdsArr[i].word < wrdsArr[j].word })
-	// сортируем по количеству слов с сохранением лексикографической соритровки слов в равновесных блоках.
-	sort.SliceStable(wrdsArr, func(i, j int) bool { return wrdsArr[i].count > wrdsArr[j].count })
+	// сортируем по количеству, слова с одинаковым количеством - по правилу tieBreak.
+	sort.Slice(rwArr, func(i, j int) bool {
+		if rwArr[i].Count != rwArr[j].Count {
+			return rwArr[i].Count > rwArr[j].Count
+		}
+
+		switch a.tieBreak {
+		case TieBreakFirstOccurrence:
+			return rwArr[i].first < rwArr[j].first
+		case TieBreakCollation:
+			if c := a.collator.CompareString(rwArr[i].Word, rwArr[j].Word); c != 0 {
+				return c < 0
+			}
+		case TieBreakLexicographic:
+		}
+		return rwArr[i].Word < rwArr[j].Word
+	})
 
-	// если массив > 10 элементов, берем первые 10.
-	if len(wrdsArr) > 10 {
-		wrdsArr = wrdsArr[:10]
+	// если массив > n элементов, берем первые n.
+	if len(rwArr) > n {
+		rwArr = rwArr[:n]
 	}
 
-	// формируем массив строк из массива объектов.
-	for _, wrds := range wrdsArr {
-		outArr = append(outArr, wrds.word)
+	wcArr := make([]WordCount, 0, len(rwArr))
+	for _, rw := range rwArr {
+		wcArr = append(wcArr, rw.WordCount)
 	}
 
-	return outArr
+	return wcArr
 }
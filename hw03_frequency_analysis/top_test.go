@@ -97,3 +97,76 @@ func TestTop10(t *testing.T) {
 		require.Equal(t, expected, Top10(sanskritTest))
 	})
 }
+
+func TestTopN(t *testing.T) {
+	text := "ёж Ель яма ель ёж дом Дом яма ель какой-то какойто"
+
+	t.Run("counts", func(t *testing.T) {
+		expected := []WordCount{
+			{Word: "the", Count: 9}, {Word: "go", Count: 7}, {Word: "of", Count: 7},
+			{Word: "is", Count: 6}, {Word: "a", Count: 5}, {Word: "type", Count: 5},
+		}
+		require.Equal(t, expected, TopN(enText, 6))
+	})
+
+	t.Run("words match Top10", func(t *testing.T) {
+		for _, text := range []string{ruText, enText, sanskritTest} {
+			require.Equal(t, Top10(text), wordsOf(TopN(text, 10)))
+		}
+	})
+
+	t.Run("top 50", func(t *testing.T) {
+		result := TopN(ruText, 50)
+		require.Len(t, result, 50)
+		for i := 1; i < len(result); i++ {
+			require.GreaterOrEqual(t, result[i-1].Count, result[i].Count)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []WordCount
+	}{
+		{
+			name: "lexicographic",
+			opts: nil,
+			expected: []WordCount{
+				{"ель", 3}, {"дом", 2}, {"яма", 2}, {"ёж", 2}, {"какой-то", 1}, {"какойто", 1},
+			},
+		},
+		{
+			name: "first occurrence",
+			opts: []Option{WithTieBreak(TieBreakFirstOccurrence)},
+			expected: []WordCount{
+				{"ель", 3}, {"ёж", 2}, {"яма", 2}, {"дом", 2}, {"какой-то", 1}, {"какойто", 1},
+			},
+		},
+		{
+			name: "collation",
+			opts: []Option{WithTieBreak(TieBreakCollation)},
+			expected: []WordCount{
+				{"ель", 3}, {"дом", 2}, {"ёж", 2}, {"яма", 2}, {"какой-то", 1}, {"какойто", 1},
+			},
+		},
+		{
+			name: "collation with case",
+			opts: []Option{WithCaseFolding(false), WithCollator(BasicCollator{})},
+			expected: []WordCount{
+				{"ёж", 2}, {"ель", 2}, {"яма", 2}, {"Дом", 1}, {"дом", 1}, {"Ель", 1},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, TopN(text, 6, tc.opts...))
+		})
+	}
+
+	t.Run("zero and negative n", func(t *testing.T) {
+		require.Len(t, TopN(text, 0), 0)
+		require.Len(t, TopN(text, -1), 0)
+	})
+}
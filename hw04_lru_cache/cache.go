@@ -1,78 +1,57 @@
 package hw04lrucache
 
-import "sync"
+import (
+	"context"
+	"io"
+	"time"
 
-type Key string
-
-type Cache interface {
-	Set(key Key, value interface{}) bool
-	Get(key Key) (interface{}, bool)
-	Clear()
-}
-
-// Структура quValue для хранения в списке пар ключ, значение
-// для обеспечения сложности О(1) при операциях с кешем.
-type quValue struct {
-	key   Key
-	value interface{}
-}
-
-type lruCache struct {
-	mu       sync.Mutex
-	capacity int
-	queue    List
-	items    map[Key]*ListItem
-}
+	"github.com/fixme_my_friend/hw04_lru_cache/lru"
+)
 
-func NewCache(capacity int) Cache {
-	return &lruCache{
-		capacity: capacity,
-		queue:    NewList(),
-		items:    make(map[Key]*ListItem, capacity),
-	}
-}
-
-func (c *lruCache) Set(key Key, value interface{}) bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// если запись по ключу есть - обновляем значение и переносим её в начало очереди
-	if i, ok := c.items[key]; ok {
-		i.Value = quValue{key, value}
-		c.queue.MoveToFront(i)
-		return true
-	}
-
-	// если записи по ключу нет добавляем новую в начало очереди
-	// проверяем объем кеша. если количество записей равно capacity - удаляем крйнюю (с хвоста)
-	if c.queue.Len() == c.capacity {
-		delete(c.items, c.queue.Back().Value.(quValue).key)
-		c.queue.Remove(c.queue.Back())
-	}
+type Key string
 
-	// Добавляем новую запись в начало кеша
-	c.items[key] = c.queue.PushFront(quValue{key, value})
+// Option - параметр кэша, например lru.WithDefaultTTL или lru.WithJanitor.
+type Option = lru.Option
 
-	return false
-}
+// Stats - статистика кэша.
+type Stats = lru.Stats
 
-func (c *lruCache) Get(key Key) (interface{}, bool) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// EvictFunc - обработчик удаления записи из кэша. причины - lru.EvictCapacity, lru.EvictExpired и другие.
+type EvictFunc = lru.EvictFunc[Key, interface{}]
 
-	// если искомая запись есть в кеше - переносим её в начало
-	if i, ok := c.items[key]; ok {
-		c.queue.MoveToFront(i)
-		return i.Value.(quValue).value, true
-	}
+// Loader загружает значение по ключу, которого нет в кэше.
+type Loader = lru.Loader[Key, interface{}]
 
-	return nil, false
+// Cache - LRU-кэш с ключами Key и значениями любого типа.
+// для типизированных ключей и значений используйте lru.Cache.
+type Cache interface {
+	Set(key Key, value interface{}) bool
+	SetWithTTL(key Key, value interface{}, ttl time.Duration) bool // добавить значение со временем жизни ttl
+	Get(key Key) (interface{}, bool)
+	Clear()
+	Close()               // остановить фоновую очистку (lru.WithJanitor)
+	OnEvict(fn EvictFunc) // задать обработчик удаления записей
+
+	// получить значение, а если его нет - загрузить через loader. одновременные обращения
+	// к отсутствующему ключу ждут одну загрузку
+	GetOrLoad(ctx context.Context, key Key, loader Loader) (interface{}, error)
+
+	Delete(key Key) bool              // удалить запись. false - ключа нет в кэше
+	Peek(key Key) (interface{}, bool) // получить значение, не меняя порядок вытеснения
+	Contains(key Key) bool            // ключ есть в кэше. порядок вытеснения не меняется
+	Len() int                         // количество записей
+	Keys() []Key                      // ключи от недавно использованных к давно использованным
+	Values() []interface{}            // значения в том же порядке, что и Keys
+	Resize(capacity int)              // изменить емкость, вытеснив лишние записи
+	Stats() Stats                     // статистика обращений и удалений
+
+	// сохранить записи в порядке Keys вместе со временем устаревания (по умолчанию gob). типы значений,
+	// кроме встроенных, нужно зарегистрировать через gob.Register
+	Snapshot(w io.Writer) error
+	Restore(r io.Reader) error // добавить записи из снимка Snapshot
 }
 
-func (c *lruCache) Clear() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.queue = NewList()
-	c.items = make(map[Key]*ListItem, c.capacity)
+// NewCache создает кэш на capacity записей с параметрами opts. обертка над lru.NewCache.
+func NewCache(capacity int, opts ...Option) Cache {
+	return lru.NewCache[Key, interface{}](capacity, opts...)
 }
@@ -1,11 +1,15 @@
 package hw04lrucache
 
 import (
+	"bytes"
+	"context"
 	"math/rand"
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/fixme_my_friend/hw04_lru_cache/lru"
 	"github.com/stretchr/testify/require"
 )
 
@@ -102,6 +106,90 @@ func TestCache(t *testing.T) {
 		require.True(t, ok)
 		require.Equal(t, val, 10)
 	})
+
+	t.Run("inspect and delete", func(t *testing.T) {
+		c := NewCache(3)
+		c.Set("1", 10)
+		c.Set("2", 20)
+		c.Set("3", 30)
+
+		val, ok := c.Peek("1")
+		require.True(t, ok)
+		require.Equal(t, 10, val)
+		require.Equal(t, []Key{"3", "2", "1"}, c.Keys())
+		require.Equal(t, []interface{}{30, 20, 10}, c.Values())
+
+		require.True(t, c.Delete("2"))
+		require.False(t, c.Contains("2"))
+		require.Equal(t, 2, c.Len())
+
+		c.Resize(1)
+		require.Equal(t, []Key{"3"}, c.Keys())
+	})
+
+	t.Run("get or load", func(t *testing.T) {
+		c := NewCache(3)
+		c.Set("1", 10)
+
+		loader := func(_ context.Context, key Key) (interface{}, error) {
+			return string(key) + "!", nil
+		}
+
+		val, err := c.GetOrLoad(context.Background(), "1", loader)
+		require.NoError(t, err)
+		require.Equal(t, 10, val)
+
+		val, err = c.GetOrLoad(context.Background(), "2", loader)
+		require.NoError(t, err)
+		require.Equal(t, "2!", val)
+		require.True(t, c.Contains("2"))
+	})
+
+	t.Run("snapshot and restore", func(t *testing.T) {
+		c := NewCache(3)
+		c.Set("1", 10)
+		c.Set("2", "20")
+		c.Get("1")
+
+		buf := bytes.Buffer{}
+		require.NoError(t, c.Snapshot(&buf))
+
+		restored := NewCache(3)
+		require.NoError(t, restored.Restore(&buf))
+		require.Equal(t, []Key{"1", "2"}, restored.Keys())
+		require.Equal(t, []interface{}{10, "20"}, restored.Values())
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		c := NewCache(3, lru.WithClock(func() time.Time { return now }), lru.WithDefaultTTL(time.Minute))
+		defer c.Close()
+
+		c.Set("1", 10)
+		c.SetWithTTL("2", 20, time.Second)
+
+		now = now.Add(time.Second)
+		require.False(t, c.Contains("2"))
+		require.True(t, c.Contains("1"))
+
+		now = now.Add(time.Minute)
+		_, ok := c.Get("1")
+		require.False(t, ok)
+	})
+
+	t.Run("on evict", func(t *testing.T) {
+		c := NewCache(1)
+		var evicted []Key
+		c.OnEvict(func(key Key, value interface{}, reason lru.EvictionReason) {
+			require.Equal(t, lru.EvictCapacity, reason)
+			require.Equal(t, 10, value)
+			evicted = append(evicted, key)
+		})
+
+		c.Set("1", 10)
+		c.Set("2", 20)
+		require.Equal(t, []Key{"1"}, evicted)
+	})
 }
 
 func TestCacheMultithreading(t *testing.T) {
@@ -0,0 +1,194 @@
+// Package dlist - обобщенный двусвязный список.
+package dlist
+
+import "iter"
+
+// List - двусвязный список значений типа T:
+//
+//	nil <- (prev) front <-> ... <-> elem <-> ... <-> back (next) -> nil
+//
+// нулевое значение - пустой список, готовый к использованию. список нельзя копировать после первого изменения.
+// элементы помнят свой список, поэтому методы с элементами других списков и nil ничего не делают,
+// а принадлежность элемента проверяется за O(1) (Contains). сложность операций - O(1), кроме
+// PushBackList и Reverse - O(n).
+type List[T any] struct {
+	front  *Item[T]
+	back   *Item[T]
+	length int
+}
+
+// Item - элемент списка.
+type Item[T any] struct {
+	Value T
+	Next  *Item[T]
+	Prev  *Item[T]
+	list  *List[T] // список, которому принадлежит элемент. nil - элемент удален
+}
+
+// New создает пустой список.
+func New[T any]() *List[T] {
+	return new(List[T])
+}
+
+// Len возвращает длину списка.
+func (l *List[T]) Len() int {
+	return l.length
+}
+
+// Front возвращает первый элемент списка или nil для пустого списка.
+func (l *List[T]) Front() *Item[T] {
+	return l.front
+}
+
+// Back возвращает последний элемент списка или nil для пустого списка.
+func (l *List[T]) Back() *Item[T] {
+	return l.back
+}
+
+// Contains сообщает, принадлежит ли элемент этому списку.
+func (l *List[T]) Contains(item *Item[T]) bool {
+	return item != nil && item.list == l
+}
+
+// PushFront добавляет значение в начало списка.
+func (l *List[T]) PushFront(v T) *Item[T] {
+	return l.link(&Item[T]{Value: v}, nil)
+}
+
+// PushBack добавляет значение в конец списка.
+func (l *List[T]) PushBack(v T) *Item[T] {
+	return l.link(&Item[T]{Value: v}, l.back)
+}
+
+// PushBackList добавляет в конец списка копии значений списка other. other может быть этим же списком.
+func (l *List[T]) PushBackList(other *List[T]) {
+	for i, n := other.Front(), other.Len(); n > 0; i, n = i.Next, n-1 {
+		l.PushBack(i.Value)
+	}
+}
+
+// InsertBefore добавляет значение перед элементом mark. если mark не из этого списка, возвращает nil.
+func (l *List[T]) InsertBefore(v T, mark *Item[T]) *Item[T] {
+	if !l.Contains(mark) {
+		return nil
+	}
+	return l.link(&Item[T]{Value: v}, mark.Prev)
+}
+
+// InsertAfter добавляет значение после элемента mark. если mark не из этого списка, возвращает nil.
+func (l *List[T]) InsertAfter(v T, mark *Item[T]) *Item[T] {
+	if !l.Contains(mark) {
+		return nil
+	}
+	return l.link(&Item[T]{Value: v}, mark)
+}
+
+// Remove удаляет элемент из списка.
+func (l *List[T]) Remove(item *Item[T]) {
+	if l.Contains(item) {
+		l.unlink(item)
+	}
+}
+
+// MoveToFront переносит элемент в начало списка.
+func (l *List[T]) MoveToFront(item *Item[T]) {
+	if !l.Contains(item) || item == l.front {
+		return
+	}
+
+	l.unlink(item)
+	l.link(item, nil)
+}
+
+// MoveToBack переносит элемент в конец списка.
+func (l *List[T]) MoveToBack(item *Item[T]) {
+	if !l.Contains(item) || item == l.back {
+		return
+	}
+
+	l.unlink(item)
+	l.link(item, l.back)
+}
+
+// MoveBefore переносит элемент item перед элементом mark. оба элемента должны быть из этого списка.
+func (l *List[T]) MoveBefore(item, mark *Item[T]) {
+	if !l.Contains(item) || !l.Contains(mark) || item == mark {
+		return
+	}
+
+	l.unlink(item)
+	l.link(item, mark.Prev)
+}
+
+// Reverse меняет порядок элементов на обратный. элементы остаются в списке.
+func (l *List[T]) Reverse() {
+	// после обмена ссылок следующий элемент - в Prev
+	for i := l.front; i != nil; i = i.Prev {
+		i.Next, i.Prev = i.Prev, i.Next
+	}
+	l.front, l.back = l.back, l.front
+}
+
+// All возвращает значения от начала списка к концу. текущий элемент можно удалить во время обхода.
+func (l *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := l.front; i != nil; {
+			next := i.Next
+			if !yield(i.Value) {
+				return
+			}
+			i = next
+		}
+	}
+}
+
+// Backward возвращает значения от конца списка к началу. текущий элемент можно удалить во время обхода.
+func (l *List[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := l.back; i != nil; {
+			prev := i.Prev
+			if !yield(i.Value) {
+				return
+			}
+			i = prev
+		}
+	}
+}
+
+// вставляем отсоединенный элемент после prev. prev == nil - в начало списка.
+func (l *List[T]) link(item, prev *Item[T]) *Item[T] {
+	item.list, item.Prev = l, prev
+
+	if prev == nil {
+		item.Next = l.front
+		l.front = item
+	} else {
+		item.Next = prev.Next
+		prev.Next = item
+	}
+	if item.Next == nil {
+		l.back = item
+	} else {
+		item.Next.Prev = item
+	}
+
+	l.length++
+	return item
+}
+
+// отсоединяем элемент этого списка.
+func (l *List[T]) unlink(item *Item[T]) {
+	if item.Prev == nil {
+		l.front = item.Next
+	} else {
+		item.Prev.Next = item.Next
+	}
+	if item.Next == nil {
+		l.back = item.Prev
+	} else {
+		item.Next.Prev = item.Prev
+	}
+
+	item.Next, item.Prev, item.list = nil, nil, nil
+	l.length--
+}
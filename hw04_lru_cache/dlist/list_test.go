@@ -0,0 +1,303 @@
+package dlist
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func values[T any](l *List[T]) []T {
+	res := make([]T, 0, l.Len())
+	for i := l.Front(); i != nil; i = i.Next {
+		res = append(res, i.Value)
+	}
+	return res
+}
+
+func TestList(t *testing.T) {
+	t.Run("zero value", func(t *testing.T) {
+		var l List[string]
+
+		l.Remove(nil)
+		l.MoveToFront(nil)
+		require.Equal(t, 0, l.Len())
+		require.Nil(t, l.Front())
+		require.Nil(t, l.Back())
+
+		l.PushBack("a")
+		require.Equal(t, "a", l.Front().Value)
+		require.Equal(t, l.Front(), l.Back())
+	})
+
+	t.Run("complex", func(t *testing.T) {
+		l := New[int]()
+
+		l.PushFront(10) // [10]
+		l.PushBack(20)  // [10, 20]
+		l.PushBack(30)  // [10, 20, 30]
+		require.Equal(t, 3, l.Len())
+
+		middle := l.Front().Next // 20
+		l.Remove(middle)         // [10, 30]
+		require.Equal(t, 2, l.Len())
+		require.Nil(t, middle.Next)
+		require.Nil(t, middle.Prev)
+
+		for i, v := range [...]int{40, 50, 60, 70, 80} {
+			if i%2 == 0 {
+				l.PushFront(v)
+			} else {
+				l.PushBack(v)
+			}
+		} // [80, 60, 40, 10, 30, 50, 70]
+		require.Equal(t, []int{80, 60, 40, 10, 30, 50, 70}, values(l))
+
+		l.MoveToFront(l.Front()) // [80, 60, 40, 10, 30, 50, 70]
+		l.MoveToFront(l.Back())  // [70, 80, 60, 40, 10, 30, 50]
+		l.MoveToFront(l.Front().Next.Next)
+		require.Equal(t, []int{60, 70, 80, 40, 10, 30, 50}, values(l))
+		require.Equal(t, 7, l.Len())
+
+		// обратный обход
+		back := make([]int, 0, l.Len())
+		for i := l.Back(); i != nil; i = i.Prev {
+			back = append(back, i.Value)
+		}
+		require.Equal(t, []int{50, 30, 10, 40, 80, 70, 60}, back)
+
+		for l.Len() > 0 {
+			l.Remove(l.Back())
+		}
+		require.Nil(t, l.Front())
+		require.Nil(t, l.Back())
+	})
+	t.Run("insert after", func(t *testing.T) {
+		l := New[int]()
+		first := l.PushBack(10)
+		last := l.InsertAfter(30, first) // [10, 30]
+		require.Equal(t, last, l.Back())
+
+		l.InsertAfter(20, first) // [10, 20, 30]
+		l.InsertAfter(40, last)  // [10, 20, 30, 40]
+		require.Equal(t, []int{10, 20, 30, 40}, values(l))
+		require.Equal(t, 4, l.Len())
+		require.Equal(t, 40, l.Back().Value)
+		require.Equal(t, 30, l.Back().Prev.Value)
+	})
+
+	t.Run("insert before and move", func(t *testing.T) {
+		l := New[int]()
+		last := l.PushBack(30)
+		first := l.InsertBefore(10, last) // [10, 30]
+		require.Equal(t, first, l.Front())
+		l.InsertBefore(20, last) // [10, 20, 30]
+		require.Equal(t, []int{10, 20, 30}, values(l))
+
+		l.MoveToBack(first) // [20, 30, 10]
+		l.MoveToBack(first)
+		require.Equal(t, []int{20, 30, 10}, values(l))
+
+		l.MoveBefore(first, l.Front()) // [10, 20, 30]
+		l.MoveBefore(last, last)
+		l.MoveBefore(last, l.Front().Next) // [10, 30, 20]
+		require.Equal(t, []int{10, 30, 20}, values(l))
+		require.Equal(t, 20, l.Back().Value)
+		require.Equal(t, 3, l.Len())
+	})
+
+	t.Run("foreign items", func(t *testing.T) {
+		l, other := New[int](), New[int]()
+		item := l.PushBack(1)
+		foreign := other.PushBack(2)
+
+		require.True(t, l.Contains(item))
+		require.False(t, l.Contains(foreign))
+		require.False(t, l.Contains(nil))
+
+		// элементы другого списка игнорируются
+		require.Nil(t, l.InsertBefore(3, foreign))
+		require.Nil(t, l.InsertAfter(3, foreign))
+		l.Remove(foreign)
+		l.MoveToFront(foreign)
+		l.MoveToBack(foreign)
+		l.MoveBefore(foreign, item)
+		l.MoveBefore(item, foreign)
+		require.Equal(t, []int{1}, values(l))
+		require.Equal(t, []int{2}, values(other))
+
+		// удаленный элемент больше не принадлежит списку
+		l.Remove(item)
+		require.False(t, l.Contains(item))
+		l.Remove(item)
+		require.Equal(t, 0, l.Len())
+	})
+
+	t.Run("push back list and reverse", func(t *testing.T) {
+		l, other := New[int](), New[int]()
+		l.PushBack(1)
+		l.PushBack(2)
+		other.PushBack(3)
+
+		l.PushBackList(other) // [1, 2, 3]
+		l.PushBackList(l)     // [1, 2, 3, 1, 2, 3]
+		require.Equal(t, []int{1, 2, 3, 1, 2, 3}, values(l))
+		require.Equal(t, []int{3}, values(other))
+
+		l.Remove(l.Back())
+		l.Reverse() // [2, 1, 3, 2, 1]
+		require.Equal(t, []int{2, 1, 3, 2, 1}, values(l))
+		require.Equal(t, 1, l.Back().Value)
+		require.Nil(t, l.Front().Prev)
+
+		var empty List[int]
+		empty.Reverse()
+		empty.PushBackList(&empty)
+		require.Equal(t, 0, empty.Len())
+	})
+
+	t.Run("iterators", func(t *testing.T) {
+		l := New[int]()
+		for i := 1; i <= 5; i++ {
+			l.PushBack(i)
+		}
+
+		require.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(l.All()))
+		require.Equal(t, []int{5, 4, 3, 2, 1}, slices.Collect(l.Backward()))
+
+		// досрочное завершение обхода
+		var first []int
+		for v := range l.All() {
+			if v > 2 {
+				break
+			}
+			first = append(first, v)
+		}
+		require.Equal(t, []int{1, 2}, first)
+
+		// удаление текущего элемента во время обхода
+		removed := 0
+		for range l.All() {
+			l.Remove(l.Front())
+			removed++
+		}
+		require.Equal(t, 5, removed)
+		require.Equal(t, 0, l.Len())
+	})
+}
+
+// элемент для операции fuzz-теста: элемент списка по номеру, элемент другого списка или nil.
+func pick(items []*Item[int], foreign *Item[int], b byte) *Item[int] {
+	switch {
+	case b == 0:
+		return nil
+	case b == 1 || len(items) == 0:
+		return foreign
+	default:
+		return items[int(b)%len(items)]
+	}
+}
+
+// выполняем операцию над списком и моделью - срезом элементов в порядке списка.
+func apply(l *List[int], items []*Item[int], foreign *Item[int], op, arg byte, v int) []*Item[int] {
+	item, mark := pick(items, foreign, arg), pick(items, foreign, arg/2)
+	at := slices.Index(items, item)
+	markAt := slices.Index(items, mark)
+
+	switch op % 10 {
+	case 0:
+		return slices.Insert(items, 0, l.PushFront(v))
+	case 1:
+		return append(items, l.PushBack(v))
+	case 2:
+		if inserted := l.InsertBefore(v, item); at >= 0 {
+			return slices.Insert(items, at, inserted)
+		}
+	case 3:
+		if inserted := l.InsertAfter(v, item); at >= 0 {
+			return slices.Insert(items, at+1, inserted)
+		}
+	case 4:
+		if l.Remove(item); at >= 0 {
+			return slices.Delete(items, at, at+1)
+		}
+	case 5:
+		if l.MoveToFront(item); at >= 0 {
+			return slices.Insert(slices.Delete(items, at, at+1), 0, item)
+		}
+	case 6:
+		if l.MoveToBack(item); at >= 0 {
+			return append(slices.Delete(items, at, at+1), item)
+		}
+	case 7:
+		if l.MoveBefore(item, mark); at >= 0 && markAt >= 0 && at != markAt {
+			items = slices.Delete(items, at, at+1)
+			return slices.Insert(items, slices.Index(items, mark), item)
+		}
+	case 8:
+		l.PushBackList(l)
+		for i, n := l.Back(), len(items); n > 0; i, n = i.Prev, n-1 {
+			items = append(items, i)
+		}
+		slices.Reverse(items[len(items)/2:])
+	case 9:
+		l.Reverse()
+		slices.Reverse(items)
+	}
+	return items
+}
+
+// проверяем связи элементов, длину и итераторы списка по модели.
+func checkList(t *testing.T, l *List[int], items []*Item[int]) {
+	t.Helper()
+
+	require.Equal(t, len(items), l.Len())
+	if len(items) == 0 {
+		require.Nil(t, l.Front())
+		require.Nil(t, l.Back())
+		return
+	}
+	require.Equal(t, items[0], l.Front())
+	require.Equal(t, items[len(items)-1], l.Back())
+
+	vals := make([]int, 0, len(items))
+	var prev *Item[int]
+	for i, item := range items {
+		require.True(t, l.Contains(item))
+		require.Equal(t, prev, item.Prev)
+		if i < len(items)-1 {
+			require.Equal(t, items[i+1], item.Next)
+		} else {
+			require.Nil(t, item.Next)
+		}
+		vals = append(vals, item.Value)
+		prev = item
+	}
+
+	require.Equal(t, vals, slices.Collect(l.All()))
+	slices.Reverse(vals)
+	require.Equal(t, vals, slices.Collect(l.Backward()))
+}
+
+func FuzzList(f *testing.F) {
+	f.Add([]byte{0, 0, 1, 0, 1, 0, 2, 3, 3, 2, 5, 4, 6, 2, 7, 5, 8, 0, 9, 0, 4, 3, 4, 1, 4, 0})
+	f.Add([]byte{1, 0, 1, 0, 8, 0, 8, 0, 7, 9, 7, 1, 9, 0, 4, 2, 4, 2, 4, 2, 4, 2})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		if len(ops) > 512 {
+			ops = ops[:512]
+		}
+
+		l, other := New[int](), New[int]()
+		foreign := other.PushBack(-1)
+
+		var items []*Item[int]
+		for i := 0; i+1 < len(ops) && len(items) < 1024; i += 2 {
+			items = apply(l, items, foreign, ops[i], ops[i+1], i)
+			checkList(t, l, items)
+		}
+
+		require.False(t, l.Contains(foreign))
+		require.Equal(t, []int{-1}, slices.Collect(other.All()))
+	})
+}
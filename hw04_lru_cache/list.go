@@ -3,125 +3,56 @@ package hw04lrucache
 import (
 	"errors"
 	"fmt"
+	"iter"
+
+	"github.com/fixme_my_friend/hw04_lru_cache/dlist"
 )
 
 var ErrEnotherListItem = "данный элемент не принадлежит текущему списку"
 
+// List - двусвязный список значений любого типа. методы с элементами других списков и nil ничего не делают,
+// Safe-методы в этом случае возвращают ошибку.
 type List interface {
 	Len() int
 	Front() *ListItem
 	Back() *ListItem
 	PushFront(v interface{}) *ListItem
 	PushBack(v interface{}) *ListItem
+	PushBackList(other List)                              // добавить в конец значения списка other
+	InsertBefore(v interface{}, mark *ListItem) *ListItem // nil - mark не из этого списка
+	InsertAfter(v interface{}, mark *ListItem) *ListItem  // nil - mark не из этого списка
 	Remove(i *ListItem)
 	MoveToFront(i *ListItem)
-}
+	MoveToBack(i *ListItem)
+	MoveBefore(i, mark *ListItem)    // перенести i перед mark
+	Reverse()                        // развернуть список
+	All() iter.Seq[interface{}]      // значения от начала к концу
+	Backward() iter.Seq[interface{}] // значения от конца к началу
 
-type ListItem struct {
-	Value interface{}
-	Next  *ListItem
-	Prev  *ListItem
+	SafeRemove(i *ListItem) error
+	SafeMoveToFront(i *ListItem) error
+	SearchNext(startItem *ListItem, v interface{}) (*ListItem, error) // найти значение после startItem
+	SearchFirst(v interface{}) *ListItem                              // найти значение от начала списка
 }
 
+// ListItem - элемент списка с полями Value, Next и Prev.
+type ListItem = dlist.Item[interface{}]
+
+// list - список значений любого типа. обертка над dlist.List.
+// для типизированных значений используйте dlist.List.
 type list struct {
-	front  *ListItem
-	back   *ListItem
-	length int
+	dlist.List[interface{}]
 }
 
 func NewList() List {
 	return new(list)
 }
 
-func (l *list) Len() int {
-	return l.length
-}
-
-func (l *list) Back() *ListItem {
-	return l.back
-}
-
-func (l *list) Front() *ListItem {
-	return l.front
-}
-
-func (l *list) PushFront(item interface{}) *ListItem {
-	newItem := &ListItem{
-		Value: item,
-		Next:  l.front,
-	}
-
-	if l.front == nil {
-		l.front = newItem
-		l.back = l.front
-	} else {
-		l.front.Prev = newItem
-		l.front = newItem
-	}
-
-	l.length++
-	return newItem
-}
-
-func (l *list) PushBack(item interface{}) *ListItem {
-	newItem := &ListItem{
-		Value: item,
-		Prev:  l.back,
-	}
-	if l.back == nil {
-		l.back = newItem
-		l.front = l.back
-	} else {
-		l.back.Next = newItem
-		l.back = newItem
+// PushBackList добавляет в конец списка значения списка other. other может быть этим же списком.
+func (l *list) PushBackList(other List) {
+	for i, n := other.Front(), other.Len(); n > 0; i, n = i.Next, n-1 {
+		l.PushBack(i.Value)
 	}
-
-	l.length++
-	return newItem
-}
-
-func (l *list) Remove(item *ListItem) {
-	if item == nil {
-		return
-	}
-
-	if l.length == 1 {
-		l.back = nil
-		l.front = nil
-		l.length = 0
-		return
-	}
-
-	switch item {
-	case l.front:
-		l.front = item.Next
-		item.Next.Prev = nil
-		item.Next = nil
-	case l.back:
-		l.back = item.Prev
-		item.Prev.Next = nil
-		item.Prev = nil
-	default:
-		item.Prev.Next = item.Next
-		item.Next.Prev = item.Prev
-	}
-
-	l.length--
-}
-
-func (l *list) MoveToFront(item *ListItem) {
-	if item == nil {
-		return
-	}
-	if item == l.front {
-		return
-	}
-
-	l.Remove(item) // удалили элемент length уменьшилась на 1
-	l.front.Prev = item
-	item.Next = l.front
-	l.front = item
-	l.length++ // приводим length в норму после Remove()
 }
 
 //////////////////////////////////////////////////////////////////////////////////////
@@ -153,39 +84,33 @@ func (l *list) SearchNext(startItem *ListItem, v interface{}) (*ListItem, error)
 		return nil, errors.New(ErrEnotherListItem)
 	}
 
-	for i := startItem.Next; i != nil; i = i.Next {
-		if i.Value == v {
-			return i, nil
-		}
-	}
-
-	return nil, nil
+	return search(startItem.Next, v), nil
 }
 
 // возвращаем первый найденный по содержимому элемент списка.
 func (l *list) SearchFirst(v interface{}) *ListItem {
-	item, _ := l.SearchNext(l.front, v)
-	return item
+	return search(l.Front(), v)
 }
 
-// проверяем принадлежит ли указанный элемент списка текущему листу.
-func (l *list) checkItem(item *ListItem) bool {
-	if item == nil {
-		return false
-	}
-
-	for i := l.Front(); i != nil; i = i.Next {
-		if i == item {
-			return true
+// ищем элемент с указанным значением начиная с элемента from включительно.
+func search(from *ListItem, v interface{}) *ListItem {
+	for i := from; i != nil; i = i.Next {
+		if i.Value == v {
+			return i
 		}
 	}
-	return false
+	return nil
+}
+
+// проверяем принадлежит ли указанный элемент списка текущему листу. элемент помнит свой список - O(1).
+func (l *list) checkItem(item *ListItem) bool {
+	return l.Contains(item)
 }
 
 func (l list) String() string {
 	res := ""
 	for i := l.Front(); i != nil; i = i.Next {
-		if i == l.front {
+		if i == l.Front() {
 			res = fmt.Sprintf("%v", i.Value)
 		} else {
 			res += fmt.Sprintf(", %v", i.Value)
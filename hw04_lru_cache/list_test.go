@@ -2,6 +2,7 @@ package hw04lrucache
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -81,7 +82,6 @@ func TestList(t *testing.T) {
 			{"multitype", []interface{}{"-128", 10, 11.5}, "-128, 10, 11.5"},
 		}
 		for _, tc := range tests {
-			tc := tc
 			t.Run(tc.name, func(t *testing.T) {
 				l := new(list)
 				for _, v := range tc.items {
@@ -157,5 +157,33 @@ func TestList(t *testing.T) {
 
 		err = l2.SafeRemove(iLst)
 		require.NoError(t, err)
+
+		// поиск находит и первый элемент списка
+		require.Equal(t, l2.Front(), l2.SearchFirst(10))
+		require.Nil(t, l2.SearchFirst(30))
+	})
+
+	t.Run("full interface", func(t *testing.T) {
+		l := NewList()
+		middle := l.PushBack(2)
+		l.InsertBefore(1, middle)
+		l.InsertAfter(3, middle) // [1, 2, 3]
+
+		other := NewList()
+		other.PushBack(4)
+		l.PushBackList(other) // [1, 2, 3, 4]
+		require.Equal(t, "1, 2, 3, 4", fmt.Sprint(l))
+
+		l.MoveToBack(middle)              // [1, 3, 4, 2]
+		l.MoveBefore(l.Back(), l.Front()) // [2, 1, 3, 4]
+		l.Reverse()                       // [4, 3, 1, 2]
+		require.Equal(t, []interface{}{4, 3, 1, 2}, slices.Collect(l.All()))
+		require.Equal(t, []interface{}{2, 1, 3, 4}, slices.Collect(l.Backward()))
+
+		// элементы другого списка не меняют список
+		require.Nil(t, l.InsertAfter(5, other.Front()))
+		l.MoveBefore(other.Front(), l.Front())
+		require.Equal(t, 4, l.Len())
+		require.Equal(t, 1, other.Len())
 	})
 }
@@ -0,0 +1,173 @@
+package lru
+
+import "github.com/fixme_my_friend/hw04_lru_cache/dlist"
+
+// списки ARC.
+const (
+	arcT1 = iota // ключи кэша, использованные один раз
+	arcT2        // ключи кэша, использованные несколько раз
+	arcB1        // вытесненные из T1 ключи (только история, значений в кэше нет)
+	arcB2        // вытесненные из T2 ключи
+	arcLists
+)
+
+// положение ключа: список и элемент в нем.
+type arcNode[K comparable] struct {
+	list int
+	item *dlist.Item[K]
+}
+
+// arcPolicy - Adaptive Replacement Cache (Nimrod Megiddo, Dharmendra S. Modha, 2003).
+// ключи кэша делятся на недавние (T1) и частые (T2), для каждой части хранится история вытесненных ключей
+// (B1, B2). попадание в историю сдвигает целевой размер T1 (p) в сторону той части, ключи которой
+// вытесняются преждевременно.
+//
+// кэш сначала добавляет ключ, затем вызывает Evict, поэтому при выборе списка для вытеснения
+// только что добавленный ключ не учитывается - так же, как в REPLACE исходного алгоритма.
+type arcPolicy[K comparable] struct {
+	capacity int
+	p        int // целевой размер T1
+	lists    [arcLists]*dlist.List[K]
+	nodes    map[K]arcNode[K]
+
+	added   *dlist.Item[K] // элемент последнего добавленного ключа. nil - ключ вытеснен
+	addedB2 bool           // последний добавленный ключ найден в истории B2
+}
+
+// NewARCPolicy создает политику ARC - адаптивное сочетание LRU и LFU.
+// ARC устойчива к однократному чтению большого количества ключей и подстраивается под смену нагрузки,
+// но хранит историю еще до capacity вытесненных ключей.
+func NewARCPolicy[K comparable]() Policy[K] {
+	p := &arcPolicy[K]{}
+	p.Reset()
+	return p
+}
+
+func (p *arcPolicy[K]) Add(key K) {
+	list := arcT1
+	p.addedB2 = false
+
+	if n, ok := p.nodes[key]; ok {
+		b1, b2 := p.lists[arcB1].Len(), p.lists[arcB2].Len()
+		switch n.list {
+		case arcB1:
+			// ключ вытеснен из T1 преждевременно - увеличиваем T1
+			p.p = minInt(p.capacity, p.p+maxInt(b2/b1, 1))
+		case arcB2:
+			// ключ вытеснен из T2 преждевременно - уменьшаем T1
+			p.p = maxInt(0, p.p-maxInt(b1/b2, 1))
+			p.addedB2 = true
+		}
+		p.unlink(key)
+		list = arcT2
+	}
+
+	p.added = p.push(list, key)
+	p.trimHistory()
+}
+
+// Access переносит ключ в начало T2.
+func (p *arcPolicy[K]) Access(key K) {
+	if n, ok := p.nodes[key]; ok && n.list <= arcT2 {
+		p.unlink(key)
+		p.push(arcT2, key)
+	}
+}
+
+func (p *arcPolicy[K]) Remove(key K) {
+	if n, ok := p.nodes[key]; ok && n.list <= arcT2 {
+		p.unlink(key)
+	}
+}
+
+// Evict вытесняет давно использованный ключ T1, если T1 больше целевого размера, иначе - давно использованный
+// ключ T2. вытесненный ключ переходит в историю.
+func (p *arcPolicy[K]) Evict() (K, bool) {
+	t1, t2 := p.lists[arcT1].Len(), p.lists[arcT2].Len()
+	if p.added != nil {
+		if n, ok := p.nodes[p.added.Value]; ok && n.item == p.added && n.list == arcT1 {
+			t1--
+		}
+	}
+
+	from, to := arcT2, arcB2
+	if t2 == 0 || (t1 > 0 && (t1 > p.p || (p.addedB2 && t1 == p.p))) {
+		from, to = arcT1, arcB1
+	}
+
+	victim := p.lists[from].Back()
+	if victim == nil {
+		var zero K
+		return zero, false
+	}
+	if victim == p.added {
+		p.added = nil
+	}
+
+	key := victim.Value
+	p.unlink(key)
+	p.push(to, key)
+	p.trimHistory()
+	return key, true
+}
+
+// Keys возвращает ключи T2, затем T1 - каждый список от недавно использованных к давно использованным.
+func (p *arcPolicy[K]) Keys() []K {
+	keys := make([]K, 0, p.lists[arcT1].Len()+p.lists[arcT2].Len())
+	keys = appendKeys(keys, p.lists[arcT2])
+	return appendKeys(keys, p.lists[arcT1])
+}
+
+func (p *arcPolicy[K]) Resize(capacity int) {
+	p.capacity = capacity
+	p.p = minInt(p.p, maxInt(capacity, 0))
+	p.trimHistory()
+}
+
+func (p *arcPolicy[K]) Reset() {
+	for i := range p.lists {
+		p.lists[i] = dlist.New[K]()
+	}
+	p.nodes = make(map[K]arcNode[K])
+	p.p = 0
+	p.added, p.addedB2 = nil, false
+}
+
+// ограничиваем историю: |T1| + |B1| <= capacity, общее количество ключей <= 2 * capacity.
+func (p *arcPolicy[K]) trimHistory() {
+	for p.lists[arcB1].Len() > 0 && p.lists[arcT1].Len()+p.lists[arcB1].Len() > p.capacity {
+		p.unlink(p.lists[arcB1].Back().Value)
+	}
+	for p.lists[arcB2].Len() > 0 && len(p.nodes) > 2*p.capacity {
+		p.unlink(p.lists[arcB2].Back().Value)
+	}
+	for p.lists[arcB1].Len() > 0 && len(p.nodes) > 2*p.capacity {
+		p.unlink(p.lists[arcB1].Back().Value)
+	}
+}
+
+func (p *arcPolicy[K]) push(list int, key K) *dlist.Item[K] {
+	item := p.lists[list].PushFront(key)
+	p.nodes[key] = arcNode[K]{list: list, item: item}
+	return item
+}
+
+func (p *arcPolicy[K]) unlink(key K) {
+	n := p.nodes[key]
+	p.lists[n.list].Remove(n.item)
+	delete(p.nodes, key)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
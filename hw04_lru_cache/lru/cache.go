@@ -0,0 +1,465 @@
+// Package lru - обобщенный кэш с вытеснением давно использованных записей (LRU) и других политик.
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	ErrEntryTooLarge  = errors.New("entry is larger than cache capacity")
+	ErrNegativeWeight = errors.New("entry weight is negative")
+)
+
+// Cache - кэш с ключами типа K и значениями типа V. безопасен для использования из нескольких горутин.
+type Cache[K comparable, V any] interface {
+	Set(key K, value V) bool                                // добавить значение. true - ключ уже был в кэше
+	SetWithTTL(key K, value V, ttl time.Duration) bool      // добавить значение со временем жизни ttl
+	TrySet(key K, value V, ttl time.Duration) (bool, error) // SetWithTTL с ошибкой, если запись не помещается
+	Get(key K) (V, bool)                                    // получить значение. false - ключа нет или он устарел
+	Clear()                                                 // очистить кэш
+	Close()                                                 // остановить фоновую очистку (WithJanitor)
+	OnEvict(fn EvictFunc[K, V])                             // задать обработчик удаления записей
+
+	// получить значение, а если его нет - загрузить через loader
+	GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error)
+
+	Delete(key K) bool    // удалить запись. false - ключа нет в кэше
+	Peek(key K) (V, bool) // получить значение, не меняя порядок вытеснения
+	Contains(key K) bool  // ключ есть в кэше. порядок вытеснения не меняется
+	Len() int             // количество записей, включая устаревшие, но еще не удаленные
+	Keys() []K            // ключи от вытесняемых последними к вытесняемым первыми
+	Values() []V          // значения в том же порядке, что и Keys
+	Resize(capacity int)  // изменить емкость, вытеснив лишние записи
+	Stats() Stats         // статистика обращений и удалений
+
+	Snapshot(w io.Writer) error // сохранить неустаревшие записи со временем устаревания (WithCodec)
+	Restore(r io.Reader) error  // добавить записи из снимка Snapshot
+}
+
+// запись кэша.
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time // момент устаревания. нулевое значение - запись не устаревает
+	weight  int64
+}
+
+// запись устарела к моменту now.
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+type lruCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int       // максимальный суммарный вес записей
+	policy   Policy[K] // порядок вытеснения записей
+	items    map[K]*entry[K, V]
+	weigher  Weigher[K, V]
+	weight   int64 // суммарный вес записей
+
+	flights  map[K]*flight[V] // загружаемые ключи (GetOrLoad)
+	failures map[K]failure    // сохраненные ошибки загрузки (WithNegativeTTL)
+	opts     options
+	stats    counters
+
+	onEvict EvictFunc[K, V]
+	pending []eviction[K, V] // удаленные под блокировкой записи для onEvict
+
+	stop      chan struct{} // закрывается для остановки фоновой очистки
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewCache создает LRU-кэш на capacity записей. при capacity <= 0 кэш ничего не хранит.
+func NewCache[K comparable, V any](capacity int, opts ...Option) Cache[K, V] {
+	return newCache[K, V](capacity, NewLRUPolicy[K](), newOptions(opts))
+}
+
+// NewCacheWithPolicy создает кэш на capacity записей, вытесняющий записи по политике policy.
+func NewCacheWithPolicy[K comparable, V any](capacity int, policy Policy[K], opts ...Option) Cache[K, V] {
+	return newCache[K, V](capacity, policy, newOptions(opts))
+}
+
+// NewWeightedCache создает LRU-кэш, суммарный вес записей которого не больше maxWeight.
+// вес записи определяет weigher, например размер значения в байтах. при добавлении записи
+// давно использованные записи вытесняются, пока новая не поместится. запись тяжелее maxWeight
+// не добавляется: TrySet возвращает ErrEntryTooLarge, запись с отрицательным весом - ErrNegativeWeight.
+// Capacity в Stats и Resize - максимальный вес. maxWeight больше math.MaxInt уменьшается до math.MaxInt.
+func NewWeightedCache[K comparable, V any](maxWeight int64, weigher Weigher[K, V], opts ...Option) Cache[K, V] {
+	// на 32-битных платформах int(maxWeight) обрезал бы старшие биты
+	if maxWeight > math.MaxInt {
+		maxWeight = math.MaxInt
+	}
+	c := newCache[K, V](int(maxWeight), NewLRUPolicy[K](), newOptions(opts))
+	c.weigher = weigher
+	return c
+}
+
+func newCache[K comparable, V any](capacity int, policy Policy[K], opts options) *lruCache[K, V] {
+	c := &lruCache[K, V]{
+		capacity: capacity,
+		policy:   policy,
+		items:    make(map[K]*entry[K, V]),
+		flights:  make(map[K]*flight[V]),
+		failures: make(map[K]failure),
+		opts:     opts,
+		stop:     make(chan struct{}),
+	}
+	c.policy.Resize(capacity)
+	c.stats.capacity.Store(int64(capacity))
+
+	if c.opts.janitorInterval > 0 {
+		c.wg.Add(1)
+		go c.janitor(c.opts.janitorInterval)
+	}
+	if c.opts.snapshotPath != "" {
+		c.wg.Add(1)
+		go c.snapshots()
+	}
+
+	return c
+}
+
+func (c *lruCache[K, V]) Set(key K, value V) bool {
+	return c.SetWithTTL(key, value, c.opts.defaultTTL)
+}
+
+// SetWithTTL добавляет значение, которое устареет через ttl. ttl <= 0 - значение не устаревает.
+// запись, которая не помещается в кэш, не добавляется.
+func (c *lruCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	wasInCache, _ := c.TrySet(key, value, ttl)
+	return wasInCache
+}
+
+// TrySet добавляет значение, которое устареет через ttl, и возвращает ErrEntryTooLarge,
+// если вес записи больше емкости кэша, или ErrNegativeWeight, если Weigher вернул отрицательный вес.
+// прежнее значение по ключу в этих случаях удаляется из кэша.
+func (c *lruCache[K, V]) TrySet(key K, value V, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.set(key, value, ttl)
+}
+
+// добавляем значение под блокировкой.
+func (c *lruCache[K, V]) set(key K, value V, ttl time.Duration) (bool, error) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.opts.now().Add(ttl)
+	}
+	return c.store(key, value, expires)
+}
+
+// добавляем значение, которое устареет в момент expires, под блокировкой.
+func (c *lruCache[K, V]) store(key K, value V, expires time.Time) (bool, error) {
+	delete(c.failures, key)
+	c.staleFlight(key)
+
+	now := c.opts.now()
+	weight := c.weigh(key, value)
+	var err error
+	switch {
+	case weight < 0:
+		// отрицательный вес уменьшил бы суммарный, и кэш вырос бы больше емкости
+		err = fmt.Errorf("%w: weight %d", ErrNegativeWeight, weight)
+	case weight > int64(c.capacity):
+		err = fmt.Errorf("%w: weight %d, capacity %d", ErrEntryTooLarge, weight, c.capacity)
+	}
+	if err != nil {
+		alive := false
+		if e, ok := c.items[key]; ok {
+			alive = !e.expired(now)
+			reason := EvictCapacity
+			if !alive {
+				reason = EvictExpired
+			}
+			c.remove(e, reason)
+		}
+		return alive, err
+	}
+
+	// если запись по ключу есть - обновляем значение и сообщаем политике об обращении.
+	// устаревшая запись считается отсутствующей
+	if e, ok := c.items[key]; ok {
+		alive := !e.expired(now)
+		if alive {
+			c.evicted(*e, EvictReplaced)
+		} else {
+			c.evicted(*e, EvictExpired)
+		}
+		e.value, e.expires = value, expires
+		c.addWeight(weight - e.weight)
+		e.weight = weight
+		c.policy.Access(key)
+		if alive {
+			c.stats.updates.Add(1)
+		} else {
+			c.stats.sets.Add(1)
+		}
+		// новое значение может быть тяжелее прежнего
+		c.evictOverflow()
+		return alive, nil
+	}
+
+	// добавляем новую запись. пока вес записей больше capacity - вытесняем выбранные политикой.
+	// политика может вытеснить и новую запись, если сочтет ее наименее ценной
+	c.stats.sets.Add(1)
+	c.items[key] = &entry[K, V]{key: key, value: value, expires: expires, weight: weight}
+	c.stats.size.Add(1)
+	c.addWeight(weight)
+	c.policy.Add(key)
+	c.evictOverflow()
+
+	return false, nil
+}
+
+func (c *lruCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.get(key); ok {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// получаем запись под блокировкой.
+func (c *lruCache[K, V]) get(key K) (*entry[K, V], bool) {
+	// если искомая запись есть в кэше - сообщаем политике об обращении. устаревшую запись удаляем
+	e, ok := c.items[key]
+	if ok && e.expired(c.opts.now()) {
+		c.remove(e, EvictExpired)
+		ok = false
+	}
+	if !ok {
+		c.stats.misses.Add(1)
+		return nil, false
+	}
+
+	c.stats.hits.Add(1)
+	c.policy.Access(key)
+	return e, true
+}
+
+func (c *lruCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	delete(c.failures, key)
+	c.staleFlight(key)
+
+	e, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	if e.expired(c.opts.now()) {
+		c.remove(e, EvictExpired)
+		return false
+	}
+
+	c.remove(e, EvictDeleted)
+	return true
+}
+
+func (c *lruCache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.lookup(key); ok {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+func (c *lruCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.lookup(key)
+	return ok
+}
+
+func (c *lruCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
+}
+
+// Keys возвращает ключи неустаревших записей в порядке политики: от вытесняемых последними
+// к вытесняемым первыми. для LRU - от недавно использованных к давно использованным.
+func (c *lruCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.opts.now()
+	keys := c.policy.Keys()
+	alive := keys[:0]
+	for _, key := range keys {
+		if !c.items[key].expired(now) {
+			alive = append(alive, key)
+		}
+	}
+	return alive
+}
+
+// Values возвращает значения неустаревших записей в том же порядке, что и Keys.
+func (c *lruCache[K, V]) Values() []V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.opts.now()
+	values := make([]V, 0, len(c.items))
+	for _, key := range c.policy.Keys() {
+		if e := c.items[key]; !e.expired(now) {
+			values = append(values, e.value)
+		}
+	}
+	return values
+}
+
+// Resize меняет емкость кэша. если записей больше новой емкости - лишние вытесняются по политике.
+func (c *lruCache[K, V]) Resize(capacity int) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = capacity
+	c.stats.capacity.Store(int64(capacity))
+	c.policy.Resize(capacity)
+	c.evictOverflow()
+}
+
+func (c *lruCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.onEvict != nil {
+		for _, e := range c.items {
+			c.evicted(*e, EvictCleared)
+		}
+	} else {
+		c.stats.evicted(EvictCleared, len(c.items))
+	}
+
+	c.stats.size.Store(0)
+	c.addWeight(-c.weight)
+	c.policy.Reset()
+	c.items = make(map[K]*entry[K, V])
+	c.failures = make(map[K]failure)
+	for _, f := range c.flights {
+		f.stale = true
+	}
+}
+
+// Close останавливает фоновую очистку и дожидается ее завершения и фоновых перезагрузок (WithRefreshAhead).
+// повторные вызовы ничего не делают.
+func (c *lruCache[K, V]) Close() {
+	c.closeOnce.Do(func() {
+		// под блокировкой: фоновая перезагрузка (WithRefreshAhead) не запускается после закрытия stop
+		c.mu.Lock()
+		close(c.stop)
+		c.mu.Unlock()
+	})
+	c.wg.Wait()
+}
+
+// фоновая очистка устаревших записей до вызова Close.
+func (c *lruCache[K, V]) janitor(interval time.Duration) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.removeExpired()
+		}
+	}
+}
+
+// удаляем все устаревшие записи и ошибки загрузки. полный обход записей - O(n).
+func (c *lruCache[K, V]) removeExpired() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.opts.now()
+	for _, e := range c.items {
+		if e.expired(now) {
+			c.remove(e, EvictExpired)
+		}
+	}
+	c.removeExpiredFailures(now)
+}
+
+// ищем неустаревшую запись без изменения порядка.
+func (c *lruCache[K, V]) lookup(key K) (*entry[K, V], bool) {
+	e, ok := c.items[key]
+	if !ok || e.expired(c.opts.now()) {
+		return nil, false
+	}
+	return e, true
+}
+
+// вес записи. без weigher каждая запись весит 1, и емкость - количество записей.
+func (c *lruCache[K, V]) weigh(key K, value V) int64 {
+	if c.weigher == nil {
+		return 1
+	}
+	return c.weigher(key, value)
+}
+
+func (c *lruCache[K, V]) addWeight(delta int64) {
+	c.weight += delta
+	c.stats.weight.Add(delta)
+}
+
+// вытесняем записи, выбранные политикой, пока их вес больше capacity.
+func (c *lruCache[K, V]) evictOverflow() {
+	for c.weight > int64(maxInt(c.capacity, 0)) {
+		key, ok := c.policy.Evict()
+		if !ok {
+			return
+		}
+		if e, ok := c.items[key]; ok {
+			c.forget(e)
+			c.evicted(*e, EvictCapacity)
+		}
+	}
+}
+
+// удаляем запись из словаря и политики.
+func (c *lruCache[K, V]) remove(e *entry[K, V], reason EvictionReason) {
+	c.policy.Remove(e.key)
+	c.forget(e)
+	c.evicted(*e, reason)
+}
+
+// удаляем запись из словаря.
+func (c *lruCache[K, V]) forget(e *entry[K, V]) {
+	delete(c.items, e.key)
+	c.stats.size.Add(-1)
+	c.addWeight(-e.weight)
+}
+
+// учитываем удаленную запись и запоминаем ее для вызова обработчика после снятия блокировки.
+func (c *lruCache[K, V]) evicted(e entry[K, V], reason EvictionReason) {
+	if reason != EvictReplaced {
+		c.stats.evicted(reason, 1)
+	}
+	if c.onEvict != nil {
+		c.pending = append(c.pending, eviction[K, V]{entry: e, reason: reason})
+	}
+}
@@ -0,0 +1,481 @@
+package lru
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	name string
+	age  int
+}
+
+func TestCache(t *testing.T) {
+	t.Run("typed values", func(t *testing.T) {
+		c := NewCache[int, user](2)
+
+		require.False(t, c.Set(1, user{"ann", 30}))
+		require.False(t, c.Set(2, user{"bob", 40}))
+
+		u, ok := c.Get(1)
+		require.True(t, ok)
+		require.Equal(t, user{"ann", 30}, u)
+
+		require.True(t, c.Set(2, user{"bob", 41}))
+		u, ok = c.Get(2)
+		require.True(t, ok)
+		require.Equal(t, 41, u.age)
+
+		// отсутствующий ключ - нулевое значение
+		u, ok = c.Get(3)
+		require.False(t, ok)
+		require.Equal(t, user{}, u)
+	})
+
+	t.Run("purge logic", func(t *testing.T) {
+		c := NewCache[string, int](3)
+		for i := 1; i <= 4; i++ {
+			c.Set(strconv.Itoa(i), i*10)
+		}
+
+		_, ok := c.Get("1")
+		require.False(t, ok)
+
+		for i := 4; i >= 2; i-- {
+			val, ok := c.Get(strconv.Itoa(i))
+			require.True(t, ok)
+			require.Equal(t, i*10, val)
+		}
+
+		// 4 - давно использованная запись, вытесняется
+		c.Set("5", 50)
+		_, ok = c.Get("4")
+		require.False(t, ok)
+		_, ok = c.Get("2")
+		require.True(t, ok)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		c := NewCache[string, []byte](3)
+		c.Set("a", []byte("a"))
+		c.Clear()
+
+		val, ok := c.Get("a")
+		require.False(t, ok)
+		require.Nil(t, val)
+
+		c.Set("a", []byte("b"))
+		val, ok = c.Get("a")
+		require.True(t, ok)
+		require.Equal(t, []byte("b"), val)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		c := NewCache[string, int](3)
+		c.Set("a", 1)
+		c.Set("b", 2)
+
+		require.True(t, c.Delete("a"))
+		require.False(t, c.Delete("a"))
+		require.False(t, c.Delete("c"))
+
+		_, ok := c.Get("a")
+		require.False(t, ok)
+		require.Equal(t, 1, c.Len())
+		require.Equal(t, []string{"b"}, c.Keys())
+	})
+
+	t.Run("peek and contains", func(t *testing.T) {
+		c := NewCache[string, int](2)
+		c.Set("a", 1)
+		c.Set("b", 2)
+
+		// Peek и Contains не делают "a" недавно использованной - она вытесняется первой
+		val, ok := c.Peek("a")
+		require.True(t, ok)
+		require.Equal(t, 1, val)
+		require.True(t, c.Contains("a"))
+		require.Equal(t, []string{"b", "a"}, c.Keys())
+
+		c.Set("c", 3)
+		require.False(t, c.Contains("a"))
+		_, ok = c.Peek("a")
+		require.False(t, ok)
+	})
+
+	t.Run("keys and values", func(t *testing.T) {
+		c := NewCache[string, int](3)
+		require.Empty(t, c.Keys())
+		require.Empty(t, c.Values())
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Set("c", 3)
+		c.Get("a")
+
+		require.Equal(t, []string{"a", "c", "b"}, c.Keys())
+		require.Equal(t, []int{1, 3, 2}, c.Values())
+		require.Equal(t, 3, c.Len())
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		c := NewCache[string, int](4)
+		for i := 1; i <= 4; i++ {
+			c.Set(strconv.Itoa(i), i)
+		}
+
+		c.Resize(2)
+		require.Equal(t, []string{"4", "3"}, c.Keys())
+
+		c.Set("5", 5)
+		require.Equal(t, []string{"5", "4"}, c.Keys())
+
+		c.Resize(3)
+		c.Set("6", 6)
+		require.Equal(t, []string{"6", "5", "4"}, c.Keys())
+
+		c.Resize(0)
+		require.Zero(t, c.Len())
+		c.Set("7", 7)
+		require.Zero(t, c.Len())
+	})
+
+	t.Run("zero capacity", func(t *testing.T) {
+		c := NewCache[string, int](0)
+		require.False(t, c.Set("a", 1))
+		_, ok := c.Get("a")
+		require.False(t, ok)
+	})
+}
+
+// fakeClock - управляемый источник времени для тестов.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func TestCacheTTL(t *testing.T) {
+	t.Run("set with ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache[string, int](10, WithClock(clock.Now))
+
+		c.SetWithTTL("short", 1, time.Second)
+		c.SetWithTTL("long", 2, time.Minute)
+		c.Set("forever", 3)
+
+		clock.Advance(time.Second - 1)
+		_, ok := c.Get("short")
+		require.True(t, ok)
+
+		clock.Advance(1)
+		_, ok = c.Get("short")
+		require.False(t, ok)
+		_, ok = c.Get("long")
+		require.True(t, ok)
+
+		clock.Advance(time.Hour)
+		_, ok = c.Get("long")
+		require.False(t, ok)
+		val, ok := c.Get("forever")
+		require.True(t, ok)
+		require.Equal(t, 3, val)
+	})
+
+	t.Run("inspection skips expired", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache[string, int](10, WithClock(clock.Now))
+
+		c.SetWithTTL("short", 1, time.Second)
+		c.Set("forever", 2)
+		clock.Advance(time.Second)
+
+		require.False(t, c.Contains("short"))
+		_, ok := c.Peek("short")
+		require.False(t, ok)
+		require.Equal(t, []string{"forever"}, c.Keys())
+		require.Equal(t, []int{2}, c.Values())
+		// устаревшая запись еще не удалена
+		require.Equal(t, 2, c.Len())
+
+		require.False(t, c.Delete("short"))
+		require.Equal(t, 1, c.Len())
+	})
+
+	t.Run("default ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache[string, int](10, WithClock(clock.Now), WithDefaultTTL(time.Minute))
+
+		c.Set("a", 1)
+		c.SetWithTTL("b", 2, 0) // без ограничения
+
+		clock.Advance(time.Minute)
+		_, ok := c.Get("a")
+		require.False(t, ok)
+		_, ok = c.Get("b")
+		require.True(t, ok)
+	})
+
+	t.Run("update resets ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache[string, int](10, WithClock(clock.Now), WithDefaultTTL(time.Minute))
+
+		require.False(t, c.Set("a", 1))
+		clock.Advance(30 * time.Second)
+		require.True(t, c.Set("a", 2))
+		clock.Advance(45 * time.Second)
+
+		val, ok := c.Get("a")
+		require.True(t, ok)
+		require.Equal(t, 2, val)
+
+		// запись устарела - Set добавляет ее заново
+		clock.Advance(time.Minute)
+		require.False(t, c.Set("a", 3))
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache[string, int](10, WithClock(clock.Now), WithJanitor(time.Millisecond))
+		defer c.Close()
+
+		c.SetWithTTL("a", 1, time.Second)
+		c.Set("b", 2)
+		clock.Advance(time.Second)
+
+		lc := c.(*lruCache[string, int])
+		require.Eventually(t, func() bool {
+			lc.mu.Lock()
+			defer lc.mu.Unlock()
+			return len(lc.items) == 1
+		}, time.Second, time.Millisecond)
+
+		_, ok := c.Get("b")
+		require.True(t, ok)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		c := NewCache[string, int](10, WithJanitor(time.Millisecond))
+		c.Close()
+		c.Close()
+
+		// без фоновой очистки Close ничего не делает
+		NewCache[string, int](10).Close()
+	})
+}
+
+type evictedEntry struct {
+	key    string
+	value  int
+	reason EvictionReason
+}
+
+func TestCacheOnEvict(t *testing.T) {
+	clock := newFakeClock()
+	c := NewCache[string, int](2, WithClock(clock.Now))
+
+	var evicted []evictedEntry
+	c.OnEvict(func(key string, value int, reason EvictionReason) {
+		evicted = append(evicted, evictedEntry{key, value, reason})
+	})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("a", 10) // обработчик получает прежнее значение
+	require.Equal(t, []evictedEntry{{"a", 1, EvictReplaced}}, evicted)
+
+	c.Set("c", 3) // вытесняется b
+	require.Equal(t, evictedEntry{"b", 2, EvictCapacity}, evicted[1])
+
+	c.SetWithTTL("d", 4, time.Second) // вытесняется a
+	clock.Advance(time.Second)
+	_, ok := c.Get("d")
+	require.False(t, ok)
+	require.Equal(t, []evictedEntry{{"a", 10, EvictCapacity}, {"d", 4, EvictExpired}}, evicted[2:])
+
+	c.SetWithTTL("e", 5, time.Second)
+	clock.Advance(time.Second)
+	c.Set("e", 50) // замена устаревшего значения
+	require.Equal(t, evictedEntry{"e", 5, EvictExpired}, evicted[4])
+
+	c.Delete("c")
+	require.Equal(t, evictedEntry{"c", 3, EvictDeleted}, evicted[5])
+
+	c.Set("f", 6)
+	c.Resize(1) // вытесняется e
+	require.Equal(t, evictedEntry{"e", 50, EvictCapacity}, evicted[6])
+
+	c.Clear()
+	require.Equal(t, []evictedEntry{{"f", 6, EvictCleared}}, evicted[7:])
+
+	t.Run("callback may use cache", func(t *testing.T) {
+		c := NewCache[string, int](1)
+		c.OnEvict(func(key string, value int, _ EvictionReason) {
+			// запись вытесненного значения под другим ключом не должна приводить к взаимоблокировке
+			if key == "a" {
+				c.Set("evicted", value)
+			}
+		})
+		c.Set("a", 1)
+		c.Set("b", 2)
+
+		val, ok := c.Get("evicted")
+		require.True(t, ok)
+		require.Equal(t, 1, val)
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache[string, int](2, WithClock(clock.Now), WithJanitor(time.Millisecond))
+		defer c.Close()
+
+		expired := make(chan string, 1)
+		c.OnEvict(func(key string, _ int, reason EvictionReason) {
+			if reason == EvictExpired {
+				expired <- key
+			}
+		})
+		c.SetWithTTL("a", 1, time.Second)
+		clock.Advance(time.Second)
+
+		select {
+		case key := <-expired:
+			require.Equal(t, "a", key)
+		case <-time.After(time.Second):
+			require.Fail(t, "expired entry was not evicted by janitor")
+		}
+	})
+
+	require.Equal(t, "capacity", EvictCapacity.String())
+	require.Equal(t, "cleared", EvictCleared.String())
+	require.Equal(t, "replaced", EvictReplaced.String())
+	require.Equal(t, "unknown", EvictionReason(100).String())
+}
+
+func TestWeightedCache(t *testing.T) {
+	byLen := func(_ string, value string) int64 { return int64(len(value)) }
+
+	t.Run("evict until fits", func(t *testing.T) {
+		c := NewWeightedCache[string, string](10, byLen)
+
+		var evicted []string
+		c.OnEvict(func(key string, _ string, reason EvictionReason) {
+			if reason == EvictCapacity {
+				evicted = append(evicted, key)
+			}
+		})
+
+		c.Set("a", "aaa")
+		c.Set("b", "bbb")
+		c.Set("c", "ccc")
+		c.Get("a")
+		require.Equal(t, int64(9), c.Stats().Weight)
+
+		// для 5 единиц веса вытесняются две давно использованные записи
+		c.Set("d", "ddddd")
+		require.Equal(t, []string{"b", "c"}, evicted)
+		require.Equal(t, []string{"d", "a"}, c.Keys())
+		require.Equal(t, int64(8), c.Stats().Weight)
+
+		// новое значение тяжелее прежнего
+		c.Set("a", "aaaaaa")
+		require.Equal(t, []string{"b", "c", "d"}, evicted)
+		require.Equal(t, int64(6), c.Stats().Weight)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		c := NewWeightedCache[string, string](10, byLen)
+		c.Set("a", "aaa")
+
+		wasInCache, err := c.TrySet("b", strings.Repeat("b", 11), 0)
+		require.ErrorIs(t, err, ErrEntryTooLarge)
+		require.False(t, wasInCache)
+		require.False(t, c.Set("b", strings.Repeat("b", 11)))
+		require.False(t, c.Contains("b"))
+
+		// записи не вытесняются ради записи, которая все равно не поместится
+		require.True(t, c.Contains("a"))
+
+		// прежнее значение по ключу удаляется
+		wasInCache, err = c.TrySet("a", strings.Repeat("a", 11), 0)
+		require.ErrorIs(t, err, ErrEntryTooLarge)
+		require.True(t, wasInCache)
+		require.False(t, c.Contains("a"))
+		require.Zero(t, c.Stats().Weight)
+
+		// запись весом в емкость помещается
+		_, err = c.TrySet("c", strings.Repeat("c", 10), 0)
+		require.NoError(t, err)
+		require.True(t, c.Contains("c"))
+	})
+
+	t.Run("negative weight", func(t *testing.T) {
+		c := NewWeightedCache[string, int](10, func(_ string, value int) int64 { return int64(value) })
+		c.Set("a", 5)
+
+		wasInCache, err := c.TrySet("a", -5, 0)
+		require.ErrorIs(t, err, ErrNegativeWeight)
+		require.True(t, wasInCache)
+		require.False(t, c.Contains("a"))
+		require.Zero(t, c.Stats().Weight)
+
+		// суммарный вес не уменьшается, и кэш не растет больше емкости
+		c.Set("b", 6)
+		c.Set("c", 6)
+		require.Equal(t, []string{"c"}, c.Keys())
+		require.Equal(t, int64(6), c.Stats().Weight)
+	})
+
+	t.Run("max weight larger than int", func(t *testing.T) {
+		c := NewWeightedCache[string, string](math.MaxInt64, byLen)
+		require.Equal(t, math.MaxInt, c.Stats().Capacity)
+		c.Set("a", "aaa")
+		require.True(t, c.Contains("a"))
+	})
+
+	t.Run("resize and clear", func(t *testing.T) {
+		c := NewWeightedCache[string, string](10, byLen)
+		c.Set("a", "aaaa")
+		c.Set("b", "bbbb")
+
+		c.Resize(5)
+		require.Equal(t, []string{"b"}, c.Keys())
+		require.Equal(t, int64(4), c.Stats().Weight)
+		require.Equal(t, 5, c.Stats().Capacity)
+
+		c.Clear()
+		require.Zero(t, c.Stats().Weight)
+	})
+
+	t.Run("without weigher", func(t *testing.T) {
+		c := NewCache[string, int](0)
+		_, err := c.TrySet("a", 1, 0)
+		require.ErrorIs(t, err, ErrEntryTooLarge)
+
+		c = NewCache[string, int](2)
+		c.Set("a", 1)
+		c.Set("b", 2)
+		require.Equal(t, int64(2), c.Stats().Weight)
+	})
+}
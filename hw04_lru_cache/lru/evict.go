@@ -0,0 +1,64 @@
+package lru
+
+// EvictionReason - причина удаления записи из кэша.
+type EvictionReason uint
+
+const (
+	EvictCapacity EvictionReason = iota // вытеснена давно использованная запись при нехватке места
+	EvictExpired                        // истекло время жизни записи
+	EvictDeleted                        // запись удалена явно
+	EvictCleared                        // кэш очищен
+
+	evictionReasonCount // количество причин удаления, учитываемых в статистике (Stats.Evictions)
+
+	// значение неустаревшей записи заменено через Set. передается только обработчику OnEvict:
+	// в статистике замена учитывается в Updates, а не среди удалений
+	EvictReplaced EvictionReason = iota
+)
+
+// мапинг причин на названия.
+var evictionReasonNames = map[EvictionReason]string{
+	EvictCapacity: "capacity",
+	EvictExpired:  "expired",
+	EvictDeleted:  "deleted",
+	EvictCleared:  "cleared",
+	EvictReplaced: "replaced",
+}
+
+func (r EvictionReason) String() string {
+	if name, ok := evictionReasonNames[r]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// EvictFunc - обработчик удаления записи из кэша.
+type EvictFunc[K comparable, V any] func(key K, value V, reason EvictionReason)
+
+// удаленная запись, ожидающая вызова обработчика.
+type eviction[K comparable, V any] struct {
+	entry[K, V]
+	reason EvictionReason
+}
+
+// OnEvict задает обработчик удаления записей (nil - без обработчика). обработчик вызывается
+// после снятия блокировки кэша в горутине, вызвавшей удаление (для фоновой очистки - в горутине очистки),
+// поэтому из него можно обращаться к кэшу. при замене значения по существующему ключу обработчик
+// получает прежнее значение с причиной EvictReplaced (для устаревшей записи - EvictExpired).
+func (c *lruCache[K, V]) OnEvict(fn EvictFunc[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onEvict = fn
+}
+
+// снимаем блокировку и вызываем обработчик для записей, удаленных под блокировкой.
+func (c *lruCache[K, V]) unlock() {
+	pending, onEvict := c.pending, c.onEvict
+	c.pending = nil
+	c.mu.Unlock()
+
+	for _, e := range pending {
+		onEvict(e.key, e.value, e.reason)
+	}
+}
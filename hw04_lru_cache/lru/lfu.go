@@ -0,0 +1,98 @@
+package lru
+
+import "github.com/fixme_my_friend/hw04_lru_cache/dlist"
+
+// группа ключей с одинаковым количеством обращений.
+type lfuBucket[K comparable] struct {
+	freq int
+	keys dlist.List[K] // от недавно использованных к давно использованным
+}
+
+// положение ключа: элемент в списке группы и элемент группы в списке групп.
+type lfuNode[K comparable] struct {
+	item   *dlist.Item[K]
+	bucket *dlist.Item[*lfuBucket[K]]
+}
+
+// lfuPolicy вытесняет ключ с наименьшим количеством обращений, при равенстве - давно использованный.
+// группы упорядочены по возрастанию количества обращений, поэтому все операции - O(1)
+// (Ketan Shah, Anirban Mitra, Dhruv Matani, "An O(1) algorithm for implementing the LFU cache eviction scheme").
+type lfuPolicy[K comparable] struct {
+	buckets *dlist.List[*lfuBucket[K]]
+	nodes   map[K]lfuNode[K]
+}
+
+// NewLFUPolicy создает политику LFU (least frequently used) - вытесняется ключ с наименьшим количеством обращений.
+// LFU сохраняет часто используемые ключи при однократном чтении большого количества других ключей,
+// но долго помнит ключи, которые были популярны раньше.
+func NewLFUPolicy[K comparable]() Policy[K] {
+	return &lfuPolicy[K]{
+		buckets: dlist.New[*lfuBucket[K]](),
+		nodes:   make(map[K]lfuNode[K]),
+	}
+}
+
+func (p *lfuPolicy[K]) Add(key K) {
+	b := p.buckets.Front()
+	if b == nil || b.Value.freq != 1 {
+		b = p.buckets.PushFront(&lfuBucket[K]{freq: 1})
+	}
+	p.nodes[key] = lfuNode[K]{item: b.Value.keys.PushFront(key), bucket: b}
+}
+
+// Access переносит ключ в группу с количеством обращений на 1 больше.
+func (p *lfuPolicy[K]) Access(key K) {
+	n, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+
+	next := n.bucket.Next
+	if next == nil || next.Value.freq != n.bucket.Value.freq+1 {
+		next = p.buckets.InsertAfter(&lfuBucket[K]{freq: n.bucket.Value.freq + 1}, n.bucket)
+	}
+	p.unlink(n)
+	p.nodes[key] = lfuNode[K]{item: next.Value.keys.PushFront(key), bucket: next}
+}
+
+func (p *lfuPolicy[K]) Remove(key K) {
+	if n, ok := p.nodes[key]; ok {
+		p.unlink(n)
+		delete(p.nodes, key)
+	}
+}
+
+func (p *lfuPolicy[K]) Evict() (K, bool) {
+	b := p.buckets.Front()
+	if b == nil {
+		var zero K
+		return zero, false
+	}
+
+	key := b.Value.keys.Back().Value
+	p.Remove(key)
+	return key, true
+}
+
+func (p *lfuPolicy[K]) Keys() []K {
+	keys := make([]K, 0, len(p.nodes))
+	for b := p.buckets.Back(); b != nil; b = b.Prev {
+		keys = appendKeys(keys, &b.Value.keys)
+	}
+	return keys
+}
+
+func (p *lfuPolicy[K]) Resize(int) {}
+
+func (p *lfuPolicy[K]) Reset() {
+	p.buckets = dlist.New[*lfuBucket[K]]()
+	p.nodes = make(map[K]lfuNode[K])
+}
+
+// удаляем ключ из группы. пустая группа удаляется.
+func (p *lfuPolicy[K]) unlink(n lfuNode[K]) {
+	n.bucket.Value.keys.Remove(n.item)
+	if n.bucket.Value.keys.Len() == 0 {
+		p.buckets.Remove(n.bucket)
+	}
+}
@@ -0,0 +1,191 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrLoaderPanic = errors.New("loader panicked")
+
+// Loader загружает значение по ключу, которого нет в кэше, например из базы данных.
+type Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// загрузка значения, результат которой ждут все обратившиеся к ключу.
+type flight[V any] struct {
+	done     chan struct{} // закрывается по окончании загрузки
+	value    V
+	err      error
+	canceled bool // загрузка прервана отменой ctx загружавшего: остальные ожидающие загружают снова
+	stale    bool // ключ изменен или удален во время загрузки: результат не сохраняется. изменяется под блокировкой
+}
+
+// ошибка загрузки, сохраненная в кэше (WithNegativeTTL).
+type failure struct {
+	err     error
+	expires time.Time
+}
+
+// GetOrLoad возвращает значение по ключу, а если его нет - загружает через loader и сохраняет в кэше
+// со временем жизни по умолчанию (WithDefaultTTL). одновременные обращения к отсутствующему ключу ждут
+// одну загрузку: loader вызывается в горутине первого обратившегося с его ctx, ошибка загрузки возвращается
+// всем ожидающим. если загрузка прервана отменой ctx первого обратившегося, ожидающие с действующим ctx
+// загружают значение снова. ожидание прерывается отменой своего ctx.
+//
+// паника loader передается вызвавшему его, остальные ожидающие получают ErrLoaderPanic.
+// паника фоновой перезагрузки (WithRefreshAhead) не передается: остается прежнее значение.
+// значение, загруженное во время Set, Delete или Clear того же ключа, возвращается, но не сохраняется.
+//
+// ошибка загрузки по умолчанию не сохраняется - следующее обращение снова вызывает loader.
+// с WithNegativeTTL ошибка сохраняется и возвращается без вызова loader, пока не устареет.
+// ошибки отмены и истечения срока ctx не сохраняются.
+// с WithRefreshAhead значение, которое скоро устареет, возвращается сразу и перезагружается в фоне.
+func (c *lruCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error) {
+	for {
+		c.mu.Lock()
+
+		if e, ok := c.get(key); ok {
+			if c.needsRefresh(e) {
+				c.refresh(key, loader)
+			}
+			value := e.value
+			c.unlock()
+			return value, nil
+		}
+
+		if f, ok := c.failures[key]; ok {
+			if !f.expired(c.opts.now()) {
+				c.unlock()
+				var zero V
+				return zero, f.err
+			}
+			delete(c.failures, key)
+		}
+
+		f, ok := c.flights[key]
+		if !ok {
+			f = c.startFlight(key)
+			c.unlock()
+			return c.load(ctx, key, f, loader, true)
+		}
+		c.unlock()
+
+		select {
+		case <-f.done:
+			if f.canceled && ctx.Err() == nil {
+				continue
+			}
+			return f.value, f.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// запись скоро устареет и должна быть перезагружена в фоне.
+func (c *lruCache[K, V]) needsRefresh(e *entry[K, V]) bool {
+	return c.opts.refreshAhead > 0 && !e.expires.IsZero() && e.expires.Sub(c.opts.now()) <= c.opts.refreshAhead
+}
+
+// перезагружаем значение в фоне, если ключ еще не загружается и кэш не закрыт. вызывается под блокировкой.
+// фоновая загрузка не зависит от ctx обратившегося, ее ошибка или паника не сохраняется: остается прежнее значение.
+// Close дожидается фоновых загрузок.
+func (c *lruCache[K, V]) refresh(key K, loader Loader[K, V]) {
+	if _, ok := c.flights[key]; ok {
+		return
+	}
+	select {
+	case <-c.stop:
+		return
+	default:
+	}
+
+	f := c.startFlight(key)
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		_, _ = c.load(context.Background(), key, f, loader, false)
+	}()
+}
+
+// регистрируем загрузку ключа. вызывается под блокировкой.
+func (c *lruCache[K, V]) startFlight(key K) *flight[V] {
+	f := &flight[V]{done: make(chan struct{})}
+	c.flights[key] = f
+	return f
+}
+
+// вызываем loader и завершаем загрузку. foreground - загрузка в горутине обратившегося, а не фоновая.
+// при панике loader загрузка завершается с ErrLoaderPanic, а паника передается обратившемуся.
+// паника фоновой загрузки не передается: в фоновой горутине ее некому перехватить.
+func (c *lruCache[K, V]) load(ctx context.Context, key K, f *flight[V], loader Loader[K, V], foreground bool) (V, error) {
+	loaded := false
+	defer func() {
+		if loaded {
+			return
+		}
+		r := recover()
+		var zero V
+		c.finishFlight(key, f, zero, fmt.Errorf("%w: %v", ErrLoaderPanic, r), false)
+		if foreground {
+			panic(r)
+		}
+	}()
+
+	value, err := loader(ctx, key)
+	loaded = true
+
+	f.canceled = contextError(err) && ctx.Err() != nil
+	c.finishFlight(key, f, value, err, foreground && !contextError(err))
+	return value, err
+}
+
+// сохраняем результат загрузки и будим ожидающих. ошибка сохраняется, если remember и задан WithNegativeTTL.
+// результат устаревшей загрузки не сохраняется.
+func (c *lruCache[K, V]) finishFlight(key K, f *flight[V], value V, err error, remember bool) {
+	c.mu.Lock()
+
+	if c.flights[key] == f {
+		delete(c.flights, key)
+	}
+	switch {
+	case f.stale:
+	case err == nil:
+		// значение, которое не помещается в кэш, возвращается без сохранения
+		_, _ = c.set(key, value, c.opts.defaultTTL)
+	case remember && c.opts.negativeTTL > 0 && len(c.failures) < c.capacity:
+		c.failures[key] = failure{err: err, expires: c.opts.now().Add(c.opts.negativeTTL)}
+	}
+
+	c.unlock()
+
+	f.value, f.err = value, err
+	close(f.done)
+}
+
+// значение ключа изменено во время загрузки: результат загрузки не сохраняем. вызывается под блокировкой.
+func (c *lruCache[K, V]) staleFlight(key K) {
+	if f, ok := c.flights[key]; ok {
+		f.stale = true
+	}
+}
+
+// ошибка отмены или истечения срока ctx, а не самой загрузки.
+func contextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (f failure) expired(now time.Time) bool {
+	return !now.Before(f.expires)
+}
+
+// удаляем устаревшие ошибки загрузки. вызывается под блокировкой.
+func (c *lruCache[K, V]) removeExpiredFailures(now time.Time) {
+	for key, f := range c.failures {
+		if f.expired(now) {
+			delete(c.failures, key)
+		}
+	}
+}
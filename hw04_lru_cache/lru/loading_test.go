@@ -0,0 +1,393 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errBackend = errors.New("backend unavailable")
+
+func TestGetOrLoad(t *testing.T) {
+	t.Run("load and cache", func(t *testing.T) {
+		c := NewCache[int, string](10)
+		calls := 0
+		loader := func(_ context.Context, key int) (string, error) {
+			calls++
+			return strconv.Itoa(key), nil
+		}
+
+		for i := 0; i < 3; i++ {
+			val, err := c.GetOrLoad(context.Background(), 7, loader)
+			require.NoError(t, err)
+			require.Equal(t, "7", val)
+		}
+		require.Equal(t, 1, calls)
+
+		val, ok := c.Get(7)
+		require.True(t, ok)
+		require.Equal(t, "7", val)
+	})
+
+	t.Run("single flight", func(t *testing.T) {
+		c := NewCache[string, int](10)
+		calls := atomic.Int32{}
+		release := make(chan struct{})
+		loader := func(context.Context, string) (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, nil
+		}
+
+		const callers = 10
+		results := make(chan int, callers)
+		wg := sync.WaitGroup{}
+		wg.Add(callers)
+		for i := 0; i < callers; i++ {
+			go func() {
+				defer wg.Done()
+				val, err := c.GetOrLoad(context.Background(), "k", loader)
+				if err == nil {
+					results <- val
+				}
+			}()
+		}
+
+		// ждем, пока все обратившиеся начнут ждать загрузку
+		lc := c.(*lruCache[string, int])
+		require.Eventually(t, func() bool {
+			return calls.Load() == 1 && lc.Stats().Misses == callers
+		}, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(results)
+
+		require.Equal(t, int32(1), calls.Load())
+		n := 0
+		for val := range results {
+			require.Equal(t, 42, val)
+			n++
+		}
+		require.Equal(t, callers, n)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		c := NewCache[string, int](10)
+		calls := 0
+		loader := func(context.Context, string) (int, error) {
+			calls++
+			return 0, errBackend
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err := c.GetOrLoad(context.Background(), "k", loader)
+			require.ErrorIs(t, err, errBackend)
+		}
+		require.Equal(t, 2, calls)
+		require.False(t, c.Contains("k"))
+	})
+
+	t.Run("negative ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache[string, int](10, WithClock(clock.Now), WithNegativeTTL(time.Second))
+		calls := 0
+		fail := true
+		loader := func(context.Context, string) (int, error) {
+			calls++
+			if fail {
+				return 0, errBackend
+			}
+			return 1, nil
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err := c.GetOrLoad(context.Background(), "k", loader)
+			require.ErrorIs(t, err, errBackend)
+		}
+		require.Equal(t, 1, calls)
+
+		// ошибка устарела - загружаем снова
+		fail = false
+		clock.Advance(time.Second)
+		val, err := c.GetOrLoad(context.Background(), "k", loader)
+		require.NoError(t, err)
+		require.Equal(t, 1, val)
+		require.Equal(t, 2, calls)
+
+		// Delete и Set забывают ошибку
+		fail = true
+		c.Delete("k")
+		_, err = c.GetOrLoad(context.Background(), "k", loader)
+		require.ErrorIs(t, err, errBackend)
+		c.Set("k", 5)
+		val, err = c.GetOrLoad(context.Background(), "k", loader)
+		require.NoError(t, err)
+		require.Equal(t, 5, val)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		c := NewCache[string, int](10)
+		release := make(chan struct{})
+		started := make(chan struct{})
+		go func() {
+			_, _ = c.GetOrLoad(context.Background(), "k", func(context.Context, string) (int, error) {
+				close(started)
+				<-release
+				return 1, nil
+			})
+		}()
+		<-started
+
+		// ожидающий с отмененным контекстом не ждет окончания загрузки
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := c.GetOrLoad(ctx, "k", func(context.Context, string) (int, error) {
+			require.Fail(t, "loader must not be called twice")
+			return 0, nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+		require.Eventually(t, func() bool { return c.Contains("k") }, time.Second, time.Millisecond)
+	})
+
+	t.Run("refresh ahead", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache[string, int](10,
+			WithClock(clock.Now), WithDefaultTTL(10*time.Second), WithRefreshAhead(2*time.Second))
+
+		version := atomic.Int32{}
+		loader := func(context.Context, string) (int, error) {
+			return int(version.Add(1)), nil
+		}
+
+		val, err := c.GetOrLoad(context.Background(), "k", loader)
+		require.NoError(t, err)
+		require.Equal(t, 1, val)
+
+		// до устаревания далеко - перезагрузки нет
+		clock.Advance(7 * time.Second)
+		val, _ = c.GetOrLoad(context.Background(), "k", loader)
+		require.Equal(t, 1, val)
+		require.Equal(t, int32(1), version.Load())
+
+		// запись скоро устареет: возвращается текущее значение, новое загружается в фоне
+		clock.Advance(time.Second)
+		val, _ = c.GetOrLoad(context.Background(), "k", loader)
+		require.Equal(t, 1, val)
+		require.Eventually(t, func() bool {
+			v, _ := c.Peek("k")
+			return v == 2
+		}, time.Second, time.Millisecond)
+
+		// новое значение живет полный срок
+		clock.Advance(9 * time.Second)
+		require.True(t, c.Contains("k"))
+	})
+
+	t.Run("refresh ahead loader panic", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache[string, int](10,
+			WithClock(clock.Now), WithDefaultTTL(time.Minute), WithRefreshAhead(30*time.Second))
+
+		_, err := c.GetOrLoad(context.Background(), "k", func(context.Context, string) (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+
+		// паника фоновой перезагрузки не роняет процесс, прежнее значение остается
+		clock.Advance(45 * time.Second)
+		val, err := c.GetOrLoad(context.Background(), "k", func(context.Context, string) (int, error) {
+			panic("backend bug")
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, val)
+
+		// Close дожидается фоновой перезагрузки
+		c.Close()
+		v, ok := c.Peek("k")
+		require.True(t, ok)
+		require.Equal(t, 1, v)
+	})
+
+	t.Run("canceled load is not remembered", func(t *testing.T) {
+		c := NewCache[string, int](10, WithNegativeTTL(time.Minute))
+		loader := func(ctx context.Context, _ string) (int, error) {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+			return 1, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := c.GetOrLoad(ctx, "k", loader)
+		require.ErrorIs(t, err, context.Canceled)
+
+		val, err := c.GetOrLoad(context.Background(), "k", loader)
+		require.NoError(t, err)
+		require.Equal(t, 1, val)
+	})
+
+	t.Run("waiters reload after leader cancel", func(t *testing.T) {
+		c := NewCache[string, int](10)
+		calls := atomic.Int32{}
+		started := make(chan struct{})
+		loader := func(ctx context.Context, _ string) (int, error) {
+			if calls.Add(1) == 1 {
+				close(started)
+				<-ctx.Done()
+				return 0, ctx.Err()
+			}
+			return 7, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		leader := make(chan error, 1)
+		go func() {
+			_, err := c.GetOrLoad(ctx, "k", loader)
+			leader <- err
+		}()
+		<-started
+
+		type result struct {
+			val int
+			err error
+		}
+		waiter := make(chan result, 1)
+		go func() {
+			val, err := c.GetOrLoad(context.Background(), "k", loader)
+			waiter <- result{val, err}
+		}()
+
+		// ждем, пока второй обратившийся начнет ждать загрузку
+		lc := c.(*lruCache[string, int])
+		require.Eventually(t, func() bool { return lc.Stats().Misses == 2 }, time.Second, time.Millisecond)
+		cancel()
+
+		require.ErrorIs(t, <-leader, context.Canceled)
+		res := <-waiter
+		require.NoError(t, res.err)
+		require.Equal(t, 7, res.val)
+		require.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("loader panic", func(t *testing.T) {
+		c := NewCache[string, int](10)
+		release := make(chan struct{})
+		started := make(chan struct{})
+		panicking := func(context.Context, string) (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		}
+
+		leader := make(chan interface{}, 1)
+		go func() {
+			defer func() { leader <- recover() }()
+			_, _ = c.GetOrLoad(context.Background(), "k", panicking)
+		}()
+		<-started
+
+		waiter := make(chan error, 1)
+		go func() {
+			_, err := c.GetOrLoad(context.Background(), "k", panicking)
+			waiter <- err
+		}()
+		lc := c.(*lruCache[string, int])
+		require.Eventually(t, func() bool { return lc.Stats().Misses == 2 }, time.Second, time.Millisecond)
+		close(release)
+
+		require.Equal(t, "boom", <-leader)
+		require.ErrorIs(t, <-waiter, ErrLoaderPanic)
+
+		// ключ снова можно загрузить
+		val, err := c.GetOrLoad(context.Background(), "k", func(context.Context, string) (int, error) {
+			return 3, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, val)
+	})
+
+	t.Run("write during load wins", func(t *testing.T) {
+		c := NewCache[string, int](10)
+		release := make(chan struct{})
+		started := make(chan struct{}, 1)
+		loader := func(context.Context, string) (int, error) {
+			started <- struct{}{}
+			<-release
+			return 1, nil
+		}
+
+		load := func() chan int {
+			done := make(chan int, 1)
+			go func() {
+				val, _ := c.GetOrLoad(context.Background(), "k", loader)
+				done <- val
+			}()
+			<-started
+			return done
+		}
+
+		// значение, добавленное во время загрузки, не заменяется загруженным
+		done := load()
+		c.Set("k", 2)
+		release <- struct{}{}
+		require.Equal(t, 1, <-done)
+		val, ok := c.Peek("k")
+		require.True(t, ok)
+		require.Equal(t, 2, val)
+
+		// удаленный во время загрузки ключ не возвращается в кэш
+		c.Delete("k")
+		done = load()
+		c.Delete("k")
+		release <- struct{}{}
+		require.Equal(t, 1, <-done)
+		require.False(t, c.Contains("k"))
+	})
+
+	t.Run("close waits for refresh", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache[string, int](10,
+			WithClock(clock.Now), WithDefaultTTL(10*time.Second), WithRefreshAhead(2*time.Second))
+		c.Set("k", 1)
+
+		release := make(chan struct{})
+		started := make(chan struct{})
+		clock.Advance(9 * time.Second)
+		val, err := c.GetOrLoad(context.Background(), "k", func(context.Context, string) (int, error) {
+			close(started)
+			<-release
+			return 2, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, val)
+		<-started
+
+		closed := make(chan struct{})
+		go func() {
+			c.Close()
+			close(closed)
+		}()
+		require.Never(t, func() bool {
+			select {
+			case <-closed:
+				return true
+			default:
+				return false
+			}
+		}, 50*time.Millisecond, time.Millisecond)
+
+		close(release)
+		<-closed
+		val, _ = c.Peek("k")
+		require.Equal(t, 2, val)
+	})
+}
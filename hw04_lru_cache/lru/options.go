@@ -0,0 +1,88 @@
+package lru
+
+import "time"
+
+// Option - параметр кэша.
+type Option func(o *options)
+
+type options struct {
+	defaultTTL      time.Duration
+	now             func() time.Time
+	janitorInterval time.Duration
+	negativeTTL     time.Duration
+	refreshAhead    time.Duration
+	codec           Codec
+
+	snapshotPath     string
+	snapshotInterval time.Duration
+	snapshotErrors   func(error)
+}
+
+func newOptions(opts []Option) options {
+	o := options{now: time.Now, codec: GobCodec}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithDefaultTTL задает время жизни записей, добавленных через Set. 0 - записи не устаревают.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.defaultTTL = ttl
+	}
+}
+
+// WithClock задает источник текущего времени для проверки устаревания (по умолчанию time.Now).
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithJanitor запускает фоновую горутину, которая каждые interval удаляет устаревшие записи.
+// без нее устаревшие записи удаляются только при обращении к ним и при вытеснении.
+// горутина останавливается методом Close.
+func WithJanitor(interval time.Duration) Option {
+	return func(o *options) {
+		o.janitorInterval = interval
+	}
+}
+
+// WithNegativeTTL сохраняет ошибки загрузки GetOrLoad на время ttl: пока ошибка не устарела,
+// GetOrLoad возвращает ее без вызова загрузчика. хранится не больше capacity ошибок.
+// без параметра ошибки не сохраняются.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.negativeTTL = ttl
+	}
+}
+
+// WithRefreshAhead включает фоновую перезагрузку в GetOrLoad: если до устаревания записи осталось
+// не больше window, GetOrLoad возвращает текущее значение и перезагружает его в фоне.
+func WithRefreshAhead(window time.Duration) Option {
+	return func(o *options) {
+		o.refreshAhead = window
+	}
+}
+
+// WithCodec задает формат снимка Snapshot и Restore (по умолчанию GobCodec).
+func WithCodec(codec Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+// WithSnapshotFile запускает фоновую горутину, которая каждые interval сохраняет снимок кэша в файл path
+// (SaveFile). при Close снимок сохраняется последний раз. interval <= 0 - снимок сохраняется только при Close.
+// ошибки сохранения передаются в onError, nil - ошибки игнорируются. восстановить кэш при запуске - LoadFile.
+func WithSnapshotFile(path string, interval time.Duration, onError func(error)) Option {
+	return func(o *options) {
+		o.snapshotPath = path
+		o.snapshotInterval = interval
+		o.snapshotErrors = onError
+	}
+}
+
+// Weigher - вес записи кэша (NewWeightedCache), например размер значения в байтах. запись с отрицательным весом не добавляется (ErrNegativeWeight).
+type Weigher[K comparable, V any] func(key K, value V) int64
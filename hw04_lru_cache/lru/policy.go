@@ -0,0 +1,76 @@
+package lru
+
+import "github.com/fixme_my_friend/hw04_lru_cache/dlist"
+
+// Policy - стратегия вытеснения: отслеживает ключи кэша и выбирает, какой из них вытеснить.
+// кэш сообщает политике о добавлении и использовании ключей и вызывает Evict, пока записей больше емкости.
+// методы вызываются под блокировкой кэша, поэтому политике не нужна своя синхронизация.
+// одну политику нельзя использовать в нескольких кэшах.
+type Policy[K comparable] interface {
+	Add(key K)           // в кэш добавлен новый ключ
+	Access(key K)        // обращение к ключу кэша: Get или замена значения
+	Remove(key K)        // ключ удален из кэша не политикой: Delete, устаревание
+	Evict() (K, bool)    // выбрать ключ для вытеснения и забыть его. false - ключей нет
+	Keys() []K           // ключи от вытесняемых последними к вытесняемым первыми
+	Resize(capacity int) // емкость кэша изменилась. вытеснение лишних ключей - через Evict
+	Reset()              // кэш очищен
+}
+
+// lruPolicy вытесняет давно использованный ключ.
+type lruPolicy[K comparable] struct {
+	queue *dlist.List[K] // ключи от недавно использованных к давно использованным
+	items map[K]*dlist.Item[K]
+}
+
+// NewLRUPolicy создает политику LRU (least recently used) - вытесняется давно использованный ключ.
+// политика кэша по умолчанию.
+func NewLRUPolicy[K comparable]() Policy[K] {
+	return &lruPolicy[K]{
+		queue: dlist.New[K](),
+		items: make(map[K]*dlist.Item[K]),
+	}
+}
+
+func (p *lruPolicy[K]) Add(key K) {
+	p.items[key] = p.queue.PushFront(key)
+}
+
+func (p *lruPolicy[K]) Access(key K) {
+	p.queue.MoveToFront(p.items[key])
+}
+
+func (p *lruPolicy[K]) Remove(key K) {
+	p.queue.Remove(p.items[key])
+	delete(p.items, key)
+}
+
+func (p *lruPolicy[K]) Evict() (K, bool) {
+	i := p.queue.Back()
+	if i == nil {
+		var zero K
+		return zero, false
+	}
+
+	p.queue.Remove(i)
+	delete(p.items, i.Value)
+	return i.Value, true
+}
+
+func (p *lruPolicy[K]) Keys() []K {
+	return appendKeys(make([]K, 0, p.queue.Len()), p.queue)
+}
+
+func (p *lruPolicy[K]) Resize(int) {}
+
+func (p *lruPolicy[K]) Reset() {
+	p.queue = dlist.New[K]()
+	p.items = make(map[K]*dlist.Item[K])
+}
+
+// добавляем ключи списка от начала к концу.
+func appendKeys[K any](keys []K, l *dlist.List[K]) []K {
+	for i := l.Front(); i != nil; i = i.Next {
+		keys = append(keys, i.Value)
+	}
+	return keys
+}
@@ -0,0 +1,234 @@
+package lru
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// политики для сравнения.
+var policies = []struct {
+	name   string
+	create func() Policy[int]
+}{
+	{"lru", NewLRUPolicy[int]},
+	{"lfu", NewLFUPolicy[int]},
+	{"arc", NewARCPolicy[int]},
+	{"w-tinylfu", func() Policy[int] { return NewTinyLFUPolicy[int](nil) }},
+}
+
+func TestPolicies(t *testing.T) {
+	for _, p := range policies {
+		t.Run(p.name, func(t *testing.T) {
+			c := NewCacheWithPolicy[int, int](3, p.create())
+
+			require.False(t, c.Set(1, 10))
+			require.True(t, c.Set(1, 11))
+			val, ok := c.Get(1)
+			require.True(t, ok)
+			require.Equal(t, 11, val)
+
+			c.Set(2, 20)
+			c.Set(3, 30)
+			require.True(t, c.Delete(2))
+			require.ElementsMatch(t, []int{1, 3}, c.Keys())
+
+			c.Clear()
+			require.Zero(t, c.Len())
+			require.Empty(t, c.Keys())
+		})
+
+		t.Run(p.name+" invariants", func(t *testing.T) {
+			const capacity = 50
+			c := NewCacheWithPolicy[int, int](capacity, p.create())
+			r := rand.New(rand.NewSource(1))
+
+			for i := 0; i < 20_000; i++ {
+				k := r.Intn(200)
+				switch op := r.Intn(10); {
+				case op < 6:
+					if v, ok := c.Get(k); ok {
+						require.Equal(t, k, v)
+					}
+				case op < 9:
+					c.Set(k, k)
+				default:
+					c.Delete(k)
+				}
+
+				if i%1000 == 0 {
+					c.Resize(capacity - 10 + r.Intn(20))
+				}
+			}
+
+			lc := c.(*lruCache[int, int])
+			require.LessOrEqual(t, c.Len(), lc.capacity)
+			keys := c.Keys()
+			require.Len(t, keys, c.Len())
+			for _, k := range keys {
+				require.Contains(t, lc.items, k)
+			}
+
+			stats := c.Stats()
+			require.Equal(t, stats.Sets, stats.EvictionsTotal()+uint64(stats.Size))
+		})
+	}
+}
+
+func TestLFUPolicy(t *testing.T) {
+	c := NewCacheWithPolicy[string, int](3, NewLFUPolicy[string]())
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("c")
+
+	require.Equal(t, []string{"a", "c", "b"}, c.Keys())
+
+	// b - единственный ключ без обращений
+	c.Set("d", 4)
+	require.False(t, c.Contains("b"))
+
+	// при равной частоте вытесняется давно использованный
+	c.Set("e", 5)
+	require.False(t, c.Contains("d"))
+	require.Equal(t, []string{"a", "c", "e"}, c.Keys())
+}
+
+func TestARCPolicy(t *testing.T) {
+	c := NewCacheWithPolicy[string, int](3, NewARCPolicy[string]())
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // a - в T2
+
+	// просмотр новых ключей вытесняет только однократно использованные
+	for i := 0; i < 10; i++ {
+		c.Set(strconv.Itoa(i), i)
+	}
+	require.True(t, c.Contains("a"))
+	require.False(t, c.Contains("b"))
+	require.Equal(t, 3, c.Len())
+
+	// повторно добавленный ключ из истории попадает в T2
+	c.Set("8", 8)
+	c.Set("9", 9)
+	require.Equal(t, "a", c.Keys()[len(c.Keys())-1])
+}
+
+func TestTinyLFUPolicy(t *testing.T) {
+	c := NewCacheWithPolicy[int, int](100, NewTinyLFUPolicy[int](nil))
+
+	// часто используемые ключи
+	for round := 0; round < 10; round++ {
+		for k := 0; k < 99; k++ {
+			if _, ok := c.Get(k); !ok {
+				c.Set(k, k)
+			}
+		}
+	}
+
+	// однократно использованные ключи почти не вытесняют часто используемые. оценка частоты приближенная:
+	// новый ключ, все счетчики которого совпали со счетчиками частых ключей, допускается в кэш
+	for k := 1000; k < 2000; k++ {
+		c.Set(k, k)
+	}
+	hot := 0
+	for k := 0; k < 99; k++ {
+		if c.Contains(k) {
+			hot++
+		}
+	}
+	require.GreaterOrEqual(t, hot, 90)
+}
+
+// последовательность обращений к ключам.
+type trace struct {
+	name string
+	keys []int
+}
+
+// traces генерирует последовательности обращений для кэша емкостью capacity.
+func traces(capacity int) []trace {
+	r := rand.New(rand.NewSource(1))
+	const n = 100_000
+
+	// zipf: популярность ключей по закону Ципфа
+	zipfGen := rand.NewZipf(r, 1.1, 1, uint64(capacity*100))
+	zipf := make([]int, n)
+	for i := range zipf {
+		zipf[i] = int(zipfGen.Uint64())
+	}
+
+	// scan: обращения по закону Ципфа, прерываемые однократным чтением большого количества новых ключей
+	scan := make([]int, 0, n)
+	next := capacity * 1000
+	for len(scan) < n {
+		for i := 0; i < capacity*5; i++ {
+			scan = append(scan, int(zipfGen.Uint64()))
+		}
+		for i := 0; i < capacity*2; i++ {
+			scan = append(scan, next)
+			next++
+		}
+	}
+
+	// loop: циклическое чтение ключей, которых в полтора раза больше емкости
+	loop := make([]int, n)
+	for i := range loop {
+		loop[i] = i % (capacity * 3 / 2)
+	}
+
+	return []trace{{"zipf", zipf}, {"scan", scan[:n]}, {"loop", loop}}
+}
+
+// воспроизводим последовательность: при промахе значение загружается в кэш.
+func replay(c Cache[int, int], keys []int) float64 {
+	for _, k := range keys {
+		if _, ok := c.Get(k); !ok {
+			c.Set(k, k)
+		}
+	}
+	return c.Stats().HitRatio()
+}
+
+func TestPolicyTraces(t *testing.T) {
+	const capacity = 1000
+
+	ratios := make(map[string]map[string]float64)
+	for _, tr := range traces(capacity) {
+		ratios[tr.name] = make(map[string]float64)
+		for _, p := range policies {
+			ratios[tr.name][p.name] = replay(NewCacheWithPolicy[int, int](capacity, p.create()), tr.keys)
+		}
+	}
+
+	for _, name := range []string{"lfu", "arc", "w-tinylfu"} {
+		require.Greater(t, ratios["zipf"][name], ratios["zipf"]["lru"], name)
+		require.Greater(t, ratios["scan"][name], ratios["scan"]["lru"], name)
+	}
+
+	// LRU, LFU и ARC вытесняют каждый ключ цикла до повторного обращения к нему,
+	// W-TinyLFU не допускает в кэш новые ключи и сохраняет часть цикла
+	require.Zero(t, ratios["loop"]["lru"])
+	require.Greater(t, ratios["loop"]["w-tinylfu"], 0.5)
+}
+
+// доля попаданий политик на последовательностях обращений: go test -bench PolicyHitRatio.
+func BenchmarkPolicyHitRatio(b *testing.B) {
+	const capacity = 1000
+
+	for _, tr := range traces(capacity) {
+		for _, p := range policies {
+			b.Run(tr.name+"/"+p.name, func(b *testing.B) {
+				ratio := 0.0
+				for i := 0; i < b.N; i++ {
+					ratio = replay(NewCacheWithPolicy[int, int](capacity, p.create()), tr.keys)
+				}
+				b.ReportMetric(ratio, "hit-ratio")
+			})
+		}
+	}
+}
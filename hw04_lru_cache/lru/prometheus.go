@@ -0,0 +1,82 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidMetricName = errors.New("invalid metric name")
+
+// допустимое имя метрики Prometheus.
+var metricNameRe = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// метрика в текстовом формате Prometheus.
+type metric struct {
+	name, help, kind string
+	samples          []sample
+}
+
+type sample struct {
+	labels string // {name="value"} или пустая строка
+	value  string
+}
+
+// WritePrometheus записывает статистику в текстовом формате Prometheus (exposition format 0.0.4).
+// имена метрик начинаются с prefix, например prefix "app_cache" дает app_cache_hits_total.
+func WritePrometheus(w io.Writer, prefix string, stats Stats) error {
+	if !metricNameRe.MatchString(prefix) {
+		return fmt.Errorf("%w: %q", ErrInvalidMetricName, prefix)
+	}
+
+	evictions := make([]sample, 0, evictionReasonCount)
+	for reason := EvictionReason(0); reason < evictionReasonCount; reason++ {
+		evictions = append(evictions, sample{
+			labels: fmt.Sprintf("{reason=%q}", reason),
+			value:  strconv.FormatUint(stats.Evictions[reason], 10),
+		})
+	}
+
+	metrics := []metric{
+		{"hits_total", "Number of Get calls that found a live entry.", "counter", uintSample(stats.Hits)},
+		{
+			"misses_total", "Number of Get calls that found no entry or an expired one.", "counter",
+			uintSample(stats.Misses),
+		},
+		{"sets_total", "Number of Set calls that added a new key.", "counter", uintSample(stats.Sets)},
+		{"updates_total", "Number of Set calls that replaced a live entry.", "counter", uintSample(stats.Updates)},
+		{"evictions_total", "Number of removed entries by reason.", "counter", evictions},
+		{"size", "Number of entries in the cache.", "gauge", intSample(int64(stats.Size))},
+		{"weight", "Total weight of entries in the cache.", "gauge", intSample(stats.Weight)},
+		{
+			"capacity", "Maximum number of entries or total weight of the cache.", "gauge",
+			intSample(int64(stats.Capacity)),
+		},
+		{"hit_ratio", "Share of Get calls that found a live entry.", "gauge", []sample{
+			{value: strconv.FormatFloat(stats.HitRatio(), 'g', -1, 64)},
+		}},
+	}
+
+	sb := strings.Builder{}
+	for _, m := range metrics {
+		name := prefix + "_" + m.name
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s %s\n", name, m.help, name, m.kind)
+		for _, s := range m.samples {
+			fmt.Fprintf(&sb, "%s%s %s\n", name, s.labels, s.value)
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func uintSample(v uint64) []sample {
+	return []sample{{value: strconv.FormatUint(v, 10)}}
+}
+
+func intSample(v int64) []sample {
+	return []sample{{value: strconv.FormatInt(v, 10)}}
+}
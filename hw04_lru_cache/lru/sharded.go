@@ -0,0 +1,291 @@
+package lru
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Hasher - хеш-функция ключа для выбора сегмента ShardedCache.
+type Hasher[K comparable] func(key K) uint64
+
+// ShardedCache - LRU-кэш, разделенный на сегменты с независимыми блокировками.
+// ключ попадает в сегмент по хешу, вытеснение работает внутри сегмента, поэтому давно использованные
+// записи вытесняются приблизительно: при неравномерном распределении ключей запись может быть вытеснена
+// из заполненного сегмента раньше более старых записей других сегментов.
+type ShardedCache[K comparable, V any] struct {
+	shards []*lruCache[K, V]
+	hasher Hasher[K]
+	opts   options
+
+	stop      chan struct{} // закрывается для остановки сохранения снимков
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+var _ Cache[string, int] = (*ShardedCache[string, int])(nil)
+
+// NewShardedCache создает кэш на capacity записей из shards сегментов. емкость делится между сегментами поровну.
+// shards <= 0 - по количеству процессоров (GOMAXPROCS); сегментов не больше capacity.
+// hasher == nil - DefaultHasher. параметры opts применяются к каждому сегменту.
+func NewShardedCache[K comparable, V any](capacity, shards int, hasher Hasher[K], opts ...Option) *ShardedCache[K, V] {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	if shards > capacity {
+		shards = capacity
+	}
+	if shards < 1 {
+		shards = 1
+	}
+	if hasher == nil {
+		hasher = DefaultHasher[K]
+	}
+
+	c := &ShardedCache[K, V]{
+		shards: make([]*lruCache[K, V], shards),
+		hasher: hasher,
+		opts:   newOptions(opts),
+		stop:   make(chan struct{}),
+	}
+
+	// снимок сохраняется целиком, а не отдельно каждым сегментом
+	o := c.opts
+	o.snapshotPath = ""
+	for i := range c.shards {
+		c.shards[i] = newCache[K, V](shardCapacity(capacity, shards, i), NewLRUPolicy[K](), o)
+	}
+
+	if c.opts.snapshotPath != "" {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.opts.saveSnapshots(c, c.stop)
+		}()
+	}
+
+	return c
+}
+
+// емкость сегмента i: остаток от деления достается первым сегментам.
+func shardCapacity(capacity, shards, i int) int {
+	if capacity <= 0 {
+		return 0
+	}
+	n := capacity / shards
+	if i < capacity%shards {
+		n++
+	}
+	return n
+}
+
+// DefaultHasher - хеш ключа по умолчанию: для целых чисел и чисел с плавающей точкой - перемешивание битов
+// (-0 и +0 равны и дают один хеш), для остальных ключей - FNV-1a строки или строкового представления (fmt.Sprint).
+// для составных ключей, равные значения которых могут печататься по-разному (например, структуры
+// с полями float), нужен свой hasher.
+func DefaultHasher[K comparable](key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return hashString(k)
+	case int:
+		return mix64(uint64(k))
+	case int64:
+		return mix64(uint64(k))
+	case int32:
+		return mix64(uint64(k))
+	case uint:
+		return mix64(uint64(k))
+	case uint64:
+		return mix64(k)
+	case uint32:
+		return mix64(uint64(k))
+	case float64:
+		return hashFloat(k)
+	case float32:
+		return hashFloat(float64(k))
+	case fmt.Stringer:
+		return hashString(k.String())
+	default:
+		return hashString(fmt.Sprint(k))
+	}
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// хеш числа с плавающей точкой. -0 == +0, поэтому приводим его к +0.
+func hashFloat(f float64) uint64 {
+	if f == 0 {
+		f = 0
+	}
+	return mix64(math.Float64bits(f))
+}
+
+// перемешиваем биты числа (финализатор splitmix64), чтобы последовательные ключи
+// равномерно распределялись по сегментам.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func (c *ShardedCache[K, V]) shard(key K) *lruCache[K, V] {
+	return c.shards[c.shardIndex(key)]
+}
+
+func (c *ShardedCache[K, V]) shardIndex(key K) uint64 {
+	return c.hasher(key) % uint64(len(c.shards))
+}
+
+func (c *ShardedCache[K, V]) Set(key K, value V) bool {
+	return c.shard(key).Set(key, value)
+}
+
+func (c *ShardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	return c.shard(key).SetWithTTL(key, value, ttl)
+}
+
+func (c *ShardedCache[K, V]) TrySet(key K, value V, ttl time.Duration) (bool, error) {
+	return c.shard(key).TrySet(key, value, ttl)
+}
+
+func (c *ShardedCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+func (c *ShardedCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error) {
+	return c.shard(key).GetOrLoad(ctx, key, loader)
+}
+
+func (c *ShardedCache[K, V]) Delete(key K) bool {
+	return c.shard(key).Delete(key)
+}
+
+func (c *ShardedCache[K, V]) Peek(key K) (V, bool) {
+	return c.shard(key).Peek(key)
+}
+
+func (c *ShardedCache[K, V]) Contains(key K) bool {
+	return c.shard(key).Contains(key)
+}
+
+// Clear очищает сегменты по очереди: кэш не блокируется целиком, поэтому записи,
+// добавленные во время очистки в уже очищенные сегменты, сохраняются.
+func (c *ShardedCache[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.Clear()
+	}
+}
+
+// Close останавливает сохранение снимков (WithSnapshotFile) и фоновую очистку сегментов.
+func (c *ShardedCache[K, V]) Close() {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+	})
+	c.wg.Wait()
+
+	for _, s := range c.shards {
+		s.Close()
+	}
+}
+
+func (c *ShardedCache[K, V]) OnEvict(fn EvictFunc[K, V]) {
+	for _, s := range c.shards {
+		s.OnEvict(fn)
+	}
+}
+
+func (c *ShardedCache[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// Keys возвращает ключи неустаревших записей. порядок от недавно использованных к давно использованным
+// соблюдается только внутри сегмента: ключи сегментов идут друг за другом.
+func (c *ShardedCache[K, V]) Keys() []K {
+	keys := make([]K, 0, c.Len())
+	for _, s := range c.shards {
+		keys = append(keys, s.Keys()...)
+	}
+	return keys
+}
+
+// Values возвращает значения неустаревших записей в том же порядке, что и Keys.
+func (c *ShardedCache[K, V]) Values() []V {
+	values := make([]V, 0, c.Len())
+	for _, s := range c.shards {
+		values = append(values, s.Values()...)
+	}
+	return values
+}
+
+// Resize делит новую емкость между сегментами поровну. количество сегментов не меняется:
+// при capacity меньше количества сегментов часть сегментов ничего не хранит.
+func (c *ShardedCache[K, V]) Resize(capacity int) {
+	for i, s := range c.shards {
+		s.Resize(shardCapacity(capacity, len(c.shards), i))
+	}
+}
+
+// Stats возвращает суммарную статистику сегментов.
+func (c *ShardedCache[K, V]) Stats() Stats {
+	var stats Stats
+	for _, s := range c.shards {
+		stats = stats.add(s.Stats())
+	}
+	return stats
+}
+
+// ShardStats возвращает статистику каждого сегмента, например для проверки равномерности распределения ключей.
+func (c *ShardedCache[K, V]) ShardStats() []Stats {
+	stats := make([]Stats, len(c.shards))
+	for i, s := range c.shards {
+		stats[i] = s.Stats()
+	}
+	return stats
+}
+
+// Snapshot записывает неустаревшие записи сегментов друг за другом, как Keys. сегменты копируются по очереди,
+// поэтому снимок не согласован между сегментами при одновременных изменениях.
+func (c *ShardedCache[K, V]) Snapshot(w io.Writer) error {
+	entries := make([]snapshotEntry[K, V], 0, c.Len())
+	for _, s := range c.shards {
+		entries = append(entries, s.entries()...)
+	}
+	return writeSnapshot(w, c.opts.codec, entries)
+}
+
+// Restore распределяет записи снимка по сегментам, сохраняя порядок записей внутри сегмента.
+// снимок можно восстановить в кэш с другим количеством сегментов или в обычный кэш.
+func (c *ShardedCache[K, V]) Restore(r io.Reader) error {
+	entries, err := readSnapshot[K, V](r, c.opts.codec)
+	if err != nil {
+		return err
+	}
+
+	parts := make([][]snapshotEntry[K, V], len(c.shards))
+	for _, e := range entries {
+		i := c.shardIndex(e.Key)
+		parts[i] = append(parts[i], e)
+	}
+	for i, s := range c.shards {
+		s.mu.Lock()
+		s.restore(parts[i])
+		s.unlock()
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package lru
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// емкость и количество записей сегментов.
+func shardSizes[K comparable, V any](c *ShardedCache[K, V]) [][2]int {
+	sizes := make([][2]int, 0, len(c.shards))
+	for _, st := range c.ShardStats() {
+		sizes = append(sizes, [2]int{st.Capacity, st.Size})
+	}
+	return sizes
+}
+
+func TestShardedCache(t *testing.T) {
+	t.Run("capacity split", func(t *testing.T) {
+		c := NewShardedCache[int, int](10, 4, nil)
+		require.Equal(t, [][2]int{{3, 0}, {3, 0}, {2, 0}, {2, 0}}, shardSizes(c))
+
+		// сегментов не больше емкости
+		require.Len(t, NewShardedCache[int, int](2, 8, nil).ShardStats(), 2)
+		require.Len(t, NewShardedCache[int, int](0, 8, nil).ShardStats(), 1)
+	})
+
+	t.Run("set get delete", func(t *testing.T) {
+		c := NewShardedCache[string, int](100, 4, nil)
+		for i := 0; i < 50; i++ {
+			require.False(t, c.Set(strconv.Itoa(i), i))
+		}
+		require.True(t, c.Set("7", 70))
+		require.Equal(t, 50, c.Len())
+
+		for i := 0; i < 50; i++ {
+			val, ok := c.Get(strconv.Itoa(i))
+			require.True(t, ok)
+			if i != 7 {
+				require.Equal(t, i, val)
+			}
+		}
+
+		require.True(t, c.Contains("7"))
+		val, ok := c.Peek("7")
+		require.True(t, ok)
+		require.Equal(t, 70, val)
+
+		require.True(t, c.Delete("7"))
+		require.False(t, c.Contains("7"))
+		require.Len(t, c.Keys(), 49)
+		require.Len(t, c.Values(), 49)
+
+		c.Clear()
+		require.Zero(t, c.Len())
+	})
+
+	t.Run("eviction within shard", func(t *testing.T) {
+		// все ключи в одном сегменте: вытеснение как у обычного LRU-кэша емкостью 2
+		c := NewShardedCache[string, int](4, 2, func(string) uint64 { return 0 })
+
+		var evicted []string
+		c.OnEvict(func(key string, _ int, reason EvictionReason) {
+			require.Equal(t, EvictCapacity, reason)
+			evicted = append(evicted, key)
+		})
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Get("a")
+		c.Set("c", 3)
+
+		require.Equal(t, []string{"b"}, evicted)
+		require.Equal(t, []string{"c", "a"}, c.Keys())
+		require.Equal(t, [][2]int{{2, 2}, {2, 0}}, shardSizes(c))
+
+		stats := c.Stats()
+		require.Equal(t, uint64(1), stats.Hits)
+		require.Equal(t, uint64(3), stats.Sets)
+		require.Equal(t, uint64(1), stats.Evictions[EvictCapacity])
+		require.Equal(t, 2, stats.Size)
+		require.Equal(t, 4, stats.Capacity)
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		c := NewShardedCache[int, int](8, 2, func(k int) uint64 { return uint64(k) })
+		for i := 0; i < 8; i++ {
+			c.Set(i, i)
+		}
+
+		c.Resize(4)
+		require.Equal(t, [][2]int{{2, 2}, {2, 2}}, shardSizes(c))
+		require.ElementsMatch(t, []int{6, 4, 7, 5}, c.Keys())
+	})
+
+	t.Run("ttl options", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewShardedCache[string, int](10, 2, nil, WithClock(clock.Now), WithDefaultTTL(time.Second))
+
+		c.Set("a", 1)
+		clock.Advance(time.Second)
+		_, ok := c.Get("a")
+		require.False(t, ok)
+	})
+
+	t.Run("default hasher spreads keys", func(t *testing.T) {
+		c := NewShardedCache[int, int](1000, 4, nil)
+		for i := 0; i < 1000; i++ {
+			c.Set(i, i)
+		}
+		for _, st := range c.ShardStats() {
+			require.InDelta(t, 250, st.Size, 50)
+		}
+	})
+
+	t.Run("concurrent access", func(t *testing.T) {
+		c := NewShardedCache[int, int](100, 8, nil)
+		wrong := atomic.Int64{}
+		wg := sync.WaitGroup{}
+		wg.Add(4)
+		for g := 0; g < 4; g++ {
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 10_000; i++ {
+					k := rand.Intn(1000)
+					if i%4 == 0 {
+						c.Set(k, k)
+					} else if v, ok := c.Get(k); ok && v != k {
+						wrong.Add(1)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		require.Zero(t, wrong.Load())
+		require.LessOrEqual(t, c.Len(), 100)
+	})
+}
+
+func TestDefaultHasher(t *testing.T) {
+	require.Equal(t, DefaultHasher("abc"), DefaultHasher("abc"))
+	require.NotEqual(t, DefaultHasher("abc"), DefaultHasher("abd"))
+	require.NotEqual(t, DefaultHasher(1), DefaultHasher(2))
+	require.Equal(t, DefaultHasher(user{"ann", 30}), DefaultHasher(user{"ann", 30}))
+	require.NotEqual(t, DefaultHasher(1.5), DefaultHasher(2.5))
+
+	// равные ключи попадают в один сегмент
+	negZero := math.Copysign(0, -1)
+	require.Equal(t, DefaultHasher(0.0), DefaultHasher(negZero))
+	require.Equal(t, DefaultHasher(float32(0)), DefaultHasher(float32(negZero)))
+
+	c := NewShardedCache[float64, int](100, 8, nil)
+	c.Set(0.0, 1)
+	val, ok := c.Get(negZero)
+	require.True(t, ok)
+	require.Equal(t, 1, val)
+	require.Equal(t, 1, c.Len())
+}
+
+// сравнение кэша с одной блокировкой и сегментированного кэша при параллельном доступе.
+// 90% обращений - чтение, ключи выбираются из множества вдвое больше емкости.
+func BenchmarkCacheParallel(b *testing.B) {
+	const capacity = 10_000
+
+	caches := []struct {
+		name   string
+		create func() Cache[int, int]
+	}{
+		{"single lock", func() Cache[int, int] { return NewCache[int, int](capacity) }},
+		{"sharded 4", func() Cache[int, int] { return NewShardedCache[int, int](capacity, 4, nil) }},
+		{"sharded 16", func() Cache[int, int] { return NewShardedCache[int, int](capacity, 16, nil) }},
+		{"sharded 64", func() Cache[int, int] { return NewShardedCache[int, int](capacity, 64, nil) }},
+	}
+
+	for _, bc := range caches {
+		b.Run(bc.name, func(b *testing.B) {
+			c := bc.create()
+			for i := 0; i < capacity; i++ {
+				c.Set(i, i)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				r := rand.New(rand.NewSource(time.Now().UnixNano()))
+				for i := 0; pb.Next(); i++ {
+					k := r.Intn(2 * capacity)
+					if i%10 == 0 {
+						c.Set(k, k)
+					} else {
+						c.Get(k)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package lru
+
+const (
+	sketchDepth    = 4  // количество строк (хеш-функций)
+	sketchMaxCount = 15 // значения счетчиков ограничены 4 битами: различать большие частоты для допуска не нужно
+	sketchMinWidth = 16
+	sketchPerKey   = 4  // ширина строки на один ключ: меньше - больше коллизий и завышенных оценок
+	sketchSample   = 10 // после width * sketchSample увеличений счетчики уменьшаются вдвое
+)
+
+// countMinSketch - приближенный счетчик частот (Cormode, Muthukrishnan). оценка частоты не меньше истинной
+// (до уменьшения счетчиков вдвое). счетчики периодически уменьшаются вдвое, чтобы старые обращения
+// весили меньше новых.
+type countMinSketch struct {
+	rows      [sketchDepth][]uint8
+	mask      uint64 // ширина строки - степень двойки, индекс - младшие биты хеша
+	additions int
+	sample    int
+}
+
+// newCountMinSketch создает счетчик для примерно capacity различных ключей.
+func newCountMinSketch(capacity int) *countMinSketch {
+	width := sketchMinWidth
+	for width < capacity*sketchPerKey {
+		width *= 2
+	}
+
+	s := &countMinSketch{mask: uint64(width - 1), sample: width * sketchSample}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// индекс счетчика строки row. строки используют разные хеши, полученные перемешиванием исходного.
+func (s *countMinSketch) index(hash uint64, row int) uint64 {
+	return mix64(hash+uint64(row)*0x9e3779b97f4a7c15) & s.mask
+}
+
+func (s *countMinSketch) increment(hash uint64) {
+	for row := range s.rows {
+		if c := &s.rows[row][s.index(hash, row)]; *c < sketchMaxCount {
+			*c++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.sample {
+		s.halve()
+	}
+}
+
+// оценка частоты - минимум счетчиков ключа.
+func (s *countMinSketch) estimate(hash uint64) uint8 {
+	estimate := uint8(sketchMaxCount)
+	for row := range s.rows {
+		if c := s.rows[row][s.index(hash, row)]; c < estimate {
+			estimate = c
+		}
+	}
+	return estimate
+}
+
+func (s *countMinSketch) halve() {
+	for row := range s.rows {
+		for i := range s.rows[row] {
+			s.rows[row][i] /= 2
+		}
+	}
+	s.additions /= 2
+}
+
+func (s *countMinSketch) reset() {
+	for row := range s.rows {
+		for i := range s.rows[row] {
+			s.rows[row][i] = 0
+		}
+	}
+	s.additions = 0
+}
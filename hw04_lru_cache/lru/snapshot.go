@@ -0,0 +1,221 @@
+package lru
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var ErrSnapshotVersion = errors.New("unsupported snapshot version")
+
+// версия формата снимка. меняется при несовместимых изменениях snapshot и snapshotEntry.
+const snapshotVersion = 1
+
+// Codec - формат снимка кэша (Snapshot, Restore).
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder записывает значение в формате кодека. ему соответствуют gob.Encoder и json.Encoder.
+type Encoder interface {
+	Encode(v any) error
+}
+
+// Decoder читает значение в формате кодека. ему соответствуют gob.Decoder и json.Decoder.
+type Decoder interface {
+	Decode(v any) error
+}
+
+var (
+	// GobCodec - кодек по умолчанию. типы значений-интерфейсов нужно зарегистрировать через gob.Register.
+	GobCodec Codec = gobCodec{}
+	// JSONCodec - текстовый формат. ключи и значения должны преобразовываться в JSON и обратно без потерь.
+	JSONCodec Codec = jsonCodec{}
+)
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder { return gob.NewEncoder(w) }
+func (gobCodec) NewDecoder(r io.Reader) Decoder { return gob.NewDecoder(r) }
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder { return json.NewEncoder(w) }
+func (jsonCodec) NewDecoder(r io.Reader) Decoder { return json.NewDecoder(r) }
+
+// снимок кэша: записи от вытесняемых последними к вытесняемым первыми.
+type snapshot[K comparable, V any] struct {
+	Version int                   `json:"version"`
+	Entries []snapshotEntry[K, V] `json:"entries"`
+}
+
+type snapshotEntry[K comparable, V any] struct {
+	Key     K         `json:"key"`
+	Value   V         `json:"value"`
+	Expires time.Time `json:"expires"` // момент устаревания. нулевое значение - запись не устаревает
+}
+
+// Snapshotter - кэш, который можно сохранить и восстановить.
+type Snapshotter interface {
+	Snapshot(w io.Writer) error
+	Restore(r io.Reader) error
+}
+
+// Snapshot записывает неустаревшие записи в порядке Keys вместе с моментами устаревания.
+// записи копируются под блокировкой, а кодируются после ее снятия, поэтому медленный w не мешает работе кэша.
+func (c *lruCache[K, V]) Snapshot(w io.Writer) error {
+	return writeSnapshot(w, c.opts.codec, c.entries())
+}
+
+// Restore добавляет записи из снимка, как Set, сохраняя их порядок и моменты устаревания:
+// записи снимка становятся недавно использованными в том же порядке, в каком были в сохраненном кэше.
+// устаревшие к моменту восстановления записи и записи, которые не помещаются в кэш, пропускаются.
+// частоты обращений политик LFU, ARC и W-TinyLFU в снимок не попадают.
+func (c *lruCache[K, V]) Restore(r io.Reader) error {
+	entries, err := readSnapshot[K, V](r, c.opts.codec)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.restore(entries)
+	return nil
+}
+
+// неустаревшие записи в порядке Keys.
+func (c *lruCache[K, V]) entries() []snapshotEntry[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.opts.now()
+	entries := make([]snapshotEntry[K, V], 0, len(c.items))
+	for _, key := range c.policy.Keys() {
+		if e := c.items[key]; !e.expired(now) {
+			entries = append(entries, snapshotEntry[K, V]{Key: e.key, Value: e.value, Expires: e.expires})
+		}
+	}
+	return entries
+}
+
+// добавляем записи снимка под блокировкой. записи добавляются с конца, чтобы первая оказалась
+// использованной последней.
+func (c *lruCache[K, V]) restore(entries []snapshotEntry[K, V]) {
+	now := c.opts.now()
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if !e.Expires.IsZero() && !now.Before(e.Expires) {
+			continue
+		}
+		// запись, которая не помещается в кэш, пропускаем
+		_, _ = c.store(e.Key, e.Value, e.Expires)
+	}
+}
+
+func writeSnapshot[K comparable, V any](w io.Writer, codec Codec, entries []snapshotEntry[K, V]) error {
+	s := snapshot[K, V]{Version: snapshotVersion, Entries: entries}
+	if err := codec.NewEncoder(w).Encode(&s); err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	return nil
+}
+
+func readSnapshot[K comparable, V any](r io.Reader, codec Codec) ([]snapshotEntry[K, V], error) {
+	var s snapshot[K, V]
+	if err := codec.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("decode snapshot: %w", err)
+	}
+	if s.Version != snapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrSnapshotVersion, s.Version)
+	}
+	return s.Entries, nil
+}
+
+// SaveFile сохраняет снимок кэша в файл path. снимок пишется во временный файл в том же каталоге,
+// который затем переименовывается в path, поэтому path всегда содержит полный снимок,
+// даже если запись прервалась.
+func SaveFile(s Snapshotter, path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Name()
+	if err := writeFile(f, s); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// записываем снимок в файл, сбрасываем его на диск и закрываем файл.
+func writeFile(f *os.File, s Snapshotter) error {
+	w := bufio.NewWriter(f)
+	err := s.Snapshot(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// LoadFile восстанавливает кэш из файла path, сохраненного SaveFile или WithSnapshotFile.
+// если файла нет, возвращается ошибка, для которой errors.Is(err, fs.ErrNotExist).
+func LoadFile(s Snapshotter, path string) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return s.Restore(bufio.NewReader(f))
+}
+
+// периодическое сохранение снимка (WithSnapshotFile) до вызова Close.
+func (c *lruCache[K, V]) snapshots() {
+	defer c.wg.Done()
+	c.opts.saveSnapshots(c, c.stop)
+}
+
+// сохраняем снимок s каждые snapshotInterval, пока не закрыт stop, и последний раз - при остановке.
+func (o options) saveSnapshots(s Snapshotter, stop <-chan struct{}) {
+	var tick <-chan time.Time
+	if o.snapshotInterval > 0 {
+		ticker := time.NewTicker(o.snapshotInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-stop:
+			o.saveSnapshot(s)
+			return
+		case <-tick:
+			o.saveSnapshot(s)
+		}
+	}
+}
+
+func (o options) saveSnapshot(s Snapshotter) {
+	if err := SaveFile(s, o.snapshotPath); err != nil && o.snapshotErrors != nil {
+		o.snapshotErrors(err)
+	}
+}
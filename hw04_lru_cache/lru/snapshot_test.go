@@ -0,0 +1,166 @@
+package lru
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	for _, codec := range []struct {
+		name  string
+		codec Codec
+	}{
+		{name: "gob", codec: GobCodec},
+		{name: "json", codec: JSONCodec},
+	} {
+		t.Run(codec.name, func(t *testing.T) {
+			clock := newFakeClock()
+			opts := []Option{WithClock(clock.Now), WithCodec(codec.codec)}
+
+			c := NewCache[string, int](5, opts...)
+			c.Set("a", 1)
+			c.SetWithTTL("b", 2, time.Minute)
+			c.SetWithTTL("short", 3, time.Second)
+			c.Set("c", 4)
+			c.Get("a")
+
+			buf := bytes.Buffer{}
+			require.NoError(t, c.Snapshot(&buf))
+
+			// запись short устаревает до восстановления, у b остается полминуты
+			clock.Advance(30 * time.Second)
+			restored := NewCache[string, int](5, opts...)
+			require.NoError(t, restored.Restore(&buf))
+			require.Equal(t, []string{"a", "c", "b"}, restored.Keys())
+			require.Equal(t, []int{1, 4, 2}, restored.Values())
+
+			clock.Advance(30 * time.Second)
+			require.False(t, restored.Contains("b"))
+			require.True(t, restored.Contains("a"))
+		})
+	}
+
+	t.Run("restore into smaller cache", func(t *testing.T) {
+		c := NewCache[int, int](10)
+		for i := 0; i < 10; i++ {
+			c.Set(i, i)
+		}
+
+		buf := bytes.Buffer{}
+		require.NoError(t, c.Snapshot(&buf))
+
+		// остаются недавно использованные записи
+		restored := NewCache[int, int](3)
+		require.NoError(t, restored.Restore(&buf))
+		require.Equal(t, []int{9, 8, 7}, restored.Keys())
+	})
+
+	t.Run("sharded", func(t *testing.T) {
+		c := NewShardedCache[int, string](100, 4, nil)
+		for i := 0; i < 50; i++ {
+			c.Set(i, "v"+strings.Repeat("!", i%3))
+		}
+
+		buf := bytes.Buffer{}
+		require.NoError(t, c.Snapshot(&buf))
+		data := buf.Bytes()
+
+		// в кэш с другим количеством сегментов
+		restored := NewShardedCache[int, string](100, 3, nil)
+		require.NoError(t, restored.Restore(bytes.NewReader(data)))
+		require.ElementsMatch(t, c.Keys(), restored.Keys())
+
+		// в тот же кэш: порядок внутри сегментов сохраняется
+		same := NewShardedCache[int, string](100, 4, nil)
+		require.NoError(t, same.Restore(bytes.NewReader(data)))
+		require.Equal(t, c.Keys(), same.Keys())
+		require.Equal(t, c.Values(), same.Values())
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		c := NewCache[string, int](5, WithCodec(JSONCodec))
+		err := c.Restore(strings.NewReader(`{"version":2,"entries":[{"key":"a","value":1}]}`))
+		require.ErrorIs(t, err, ErrSnapshotVersion)
+		require.Equal(t, 0, c.Len())
+
+		err = c.Restore(strings.NewReader("not json"))
+		require.Error(t, err)
+	})
+}
+
+func TestSnapshotFile(t *testing.T) {
+	t.Run("save and load", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.snapshot")
+
+		c := NewCache[string, int](5)
+		c.Set("a", 1)
+		c.Set("b", 2)
+		require.NoError(t, SaveFile(c, path))
+
+		// повторное сохранение заменяет файл
+		c.Set("c", 3)
+		require.NoError(t, SaveFile(c, path))
+
+		restored := NewCache[string, int](5)
+		require.NoError(t, LoadFile(restored, path))
+		require.Equal(t, []string{"c", "b", "a"}, restored.Keys())
+
+		// временные файлы не остаются
+		files, err := os.ReadDir(filepath.Dir(path))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		c := NewCache[string, int](5)
+		err := LoadFile(c, filepath.Join(t.TempDir(), "missing"))
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("periodic", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.snapshot")
+		c := NewCache[string, int](5, WithSnapshotFile(path, 10*time.Millisecond, func(err error) {
+			t.Error(err)
+		}))
+		c.Set("a", 1)
+
+		require.Eventually(t, func() bool {
+			restored := NewCache[string, int](5)
+			return LoadFile(restored, path) == nil && restored.Contains("a")
+		}, time.Second, time.Millisecond)
+
+		// при Close сохраняется последнее состояние
+		c.Set("b", 2)
+		c.Close()
+
+		restored := NewCache[string, int](5)
+		require.NoError(t, LoadFile(restored, path))
+		require.Equal(t, []string{"b", "a"}, restored.Keys())
+	})
+
+	t.Run("sharded on close", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.snapshot")
+		errs := 0
+		c := NewShardedCache[int, int](10, 2, nil, WithSnapshotFile(path, 0, func(error) { errs++ }))
+		for i := 0; i < 10; i++ {
+			c.Set(i, i)
+		}
+
+		_, err := os.Stat(path)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+
+		c.Close()
+		require.Zero(t, errs)
+
+		restored := NewCache[int, int](10)
+		require.NoError(t, LoadFile(restored, path))
+		require.ElementsMatch(t, c.Keys(), restored.Keys())
+	})
+}
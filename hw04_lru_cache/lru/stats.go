@@ -0,0 +1,87 @@
+package lru
+
+import "sync/atomic"
+
+// Stats - снимок статистики кэша.
+type Stats struct {
+	Hits      uint64                    // Get нашел неустаревшую запись
+	Misses    uint64                    // Get не нашел запись или она устарела
+	Sets      uint64                    // Set добавил новый ключ (в том числе на место устаревшей записи)
+	Updates   uint64                    // Set заменил значение неустаревшей записи
+	Evictions map[EvictionReason]uint64 // количество удаленных записей по причинам
+	Size      int                       // количество записей, включая устаревшие, но еще не удаленные
+	Weight    int64                     // суммарный вес записей. без Weigher совпадает с Size
+	Capacity  int                       // емкость: количество записей или максимальный вес (NewWeightedCache)
+}
+
+// HitRatio - доля попаданий среди обращений Get. без обращений - 0.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// EvictionsTotal - количество удаленных записей по всем причинам. замененные значения учитываются в Updates.
+func (s Stats) EvictionsTotal() uint64 {
+	total := uint64(0)
+	for _, n := range s.Evictions {
+		total += n
+	}
+	return total
+}
+
+// складываем статистику, например сегментов ShardedCache.
+func (s Stats) add(other Stats) Stats {
+	s.Hits += other.Hits
+	s.Misses += other.Misses
+	s.Sets += other.Sets
+	s.Updates += other.Updates
+	s.Size += other.Size
+	s.Weight += other.Weight
+	s.Capacity += other.Capacity
+
+	evictions := make(map[EvictionReason]uint64, len(s.Evictions))
+	for _, m := range []map[EvictionReason]uint64{s.Evictions, other.Evictions} {
+		for reason, n := range m {
+			evictions[reason] += n
+		}
+	}
+	s.Evictions = evictions
+	return s
+}
+
+// счетчики кэша. изменяются под блокировкой кэша, но читаются без нее,
+// поэтому Stats не мешает работе кэша.
+type counters struct {
+	hits, misses, sets, updates atomic.Uint64
+	evictions                   [evictionReasonCount]atomic.Uint64
+	size, weight, capacity      atomic.Int64
+}
+
+func (c *counters) evicted(reason EvictionReason, n int) {
+	c.evictions[reason].Add(uint64(n))
+}
+
+func (c *counters) snapshot() Stats {
+	s := Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Sets:      c.sets.Load(),
+		Updates:   c.updates.Load(),
+		Evictions: make(map[EvictionReason]uint64, evictionReasonCount),
+		Size:      int(c.size.Load()),
+		Weight:    c.weight.Load(),
+		Capacity:  int(c.capacity.Load()),
+	}
+	for reason := EvictionReason(0); reason < evictionReasonCount; reason++ {
+		s.Evictions[reason] = c.evictions[reason].Load()
+	}
+	return s
+}
+
+// Stats возвращает статистику кэша. счетчики читаются без блокировки по отдельности,
+// поэтому при одновременных изменениях снимок может быть несогласованным (например, Size и Evictions).
+func (c *lruCache[K, V]) Stats() Stats {
+	return c.stats.snapshot()
+}
@@ -0,0 +1,136 @@
+package lru
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheStats(t *testing.T) {
+	clock := newFakeClock()
+	c := NewCache[string, int](2, WithClock(clock.Now))
+
+	stats := c.Stats()
+	require.Equal(t, 2, stats.Capacity)
+	require.Zero(t, stats.HitRatio())
+	require.Zero(t, stats.EvictionsTotal())
+
+	c.Set("a", 1)
+	c.Set("a", 2)                     // обновление
+	c.SetWithTTL("b", 3, time.Second) // добавление
+	c.Get("a")
+	c.Get("x")
+	c.Peek("a")   // Peek не учитывается
+	c.Set("c", 4) // вытесняется b
+
+	clock.Advance(time.Second)
+	c.SetWithTTL("a", 5, time.Second)
+	clock.Advance(time.Second)
+	c.Get("a") // a устарела
+	c.Delete("c")
+
+	stats = c.Stats()
+	require.Equal(t, uint64(1), stats.Hits)
+	require.Equal(t, uint64(2), stats.Misses)
+	require.Equal(t, uint64(3), stats.Sets)
+	require.Equal(t, uint64(2), stats.Updates)
+	require.Equal(t, map[EvictionReason]uint64{
+		EvictCapacity: 1, EvictExpired: 1, EvictDeleted: 1, EvictCleared: 0,
+	}, stats.Evictions)
+	require.Equal(t, uint64(3), stats.EvictionsTotal())
+	require.Zero(t, stats.Size)
+	require.InDelta(t, 1.0/3, stats.HitRatio(), 1e-9)
+
+	c.Set("d", 6)
+	c.Set("e", 7)
+	c.Clear()
+	c.Resize(5)
+
+	stats = c.Stats()
+	require.Equal(t, uint64(2), stats.Evictions[EvictCleared])
+	require.Zero(t, stats.Size)
+	require.Equal(t, 5, stats.Capacity)
+
+	t.Run("concurrent", func(t *testing.T) {
+		c := NewCache[int, int](10)
+		wg := sync.WaitGroup{}
+		wg.Add(4)
+		for g := 0; g < 4; g++ {
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					c.Set(i%20, i)
+					c.Get(i % 20)
+					c.Stats()
+				}
+			}()
+		}
+		wg.Wait()
+
+		stats := c.Stats()
+		require.Equal(t, uint64(4000), stats.Sets+stats.Updates)
+		require.Equal(t, uint64(4000), stats.Hits+stats.Misses)
+		require.Equal(t, c.Len(), stats.Size)
+		require.Equal(t, stats.Sets, stats.EvictionsTotal()+uint64(stats.Size))
+	})
+}
+
+func TestWritePrometheus(t *testing.T) {
+	stats := Stats{
+		Hits: 3, Misses: 1, Sets: 4, Updates: 2,
+		Evictions: map[EvictionReason]uint64{EvictCapacity: 5, EvictExpired: 1},
+		Size:      7, Weight: 7, Capacity: 10,
+	}
+
+	buf := bytes.Buffer{}
+	require.NoError(t, WritePrometheus(&buf, "app_cache", stats))
+	require.Equal(t, `# HELP app_cache_hits_total Number of Get calls that found a live entry.
+# TYPE app_cache_hits_total counter
+app_cache_hits_total 3
+# HELP app_cache_misses_total Number of Get calls that found no entry or an expired one.
+# TYPE app_cache_misses_total counter
+app_cache_misses_total 1
+# HELP app_cache_sets_total Number of Set calls that added a new key.
+# TYPE app_cache_sets_total counter
+app_cache_sets_total 4
+# HELP app_cache_updates_total Number of Set calls that replaced a live entry.
+# TYPE app_cache_updates_total counter
+app_cache_updates_total 2
+# HELP app_cache_evictions_total Number of removed entries by reason.
+# TYPE app_cache_evictions_total counter
+app_cache_evictions_total{reason="capacity"} 5
+app_cache_evictions_total{reason="expired"} 1
+app_cache_evictions_total{reason="deleted"} 0
+app_cache_evictions_total{reason="cleared"} 0
+# HELP app_cache_size Number of entries in the cache.
+# TYPE app_cache_size gauge
+app_cache_size 7
+# HELP app_cache_weight Total weight of entries in the cache.
+# TYPE app_cache_weight gauge
+app_cache_weight 7
+# HELP app_cache_capacity Maximum number of entries or total weight of the cache.
+# TYPE app_cache_capacity gauge
+app_cache_capacity 10
+# HELP app_cache_hit_ratio Share of Get calls that found a live entry.
+# TYPE app_cache_hit_ratio gauge
+app_cache_hit_ratio 0.75
+`, buf.String())
+
+	for _, prefix := range []string{"", "1cache", "app-cache"} {
+		err := WritePrometheus(&buf, prefix, stats)
+		require.Truef(t, errors.Is(err, ErrInvalidMetricName), "prefix %q", prefix)
+	}
+
+	// статистика кэша экспортируется целиком
+	c := NewShardedCache[int, int](4, 2, nil)
+	c.Set(1, 1)
+	buf.Reset()
+	require.NoError(t, WritePrometheus(&buf, "cache", c.Stats()))
+	require.True(t, strings.Contains(buf.String(), "\ncache_sets_total 1\n"))
+	require.True(t, strings.Contains(buf.String(), "\ncache_capacity 4\n"))
+}
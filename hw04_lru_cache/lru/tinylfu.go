@@ -0,0 +1,165 @@
+package lru
+
+import "github.com/fixme_my_friend/hw04_lru_cache/dlist"
+
+// части W-TinyLFU.
+const (
+	tinyWindow    = iota // окно: новые ключи, LRU
+	tinyProbation        // испытательная часть основного кэша
+	tinyProtected        // защищенная часть основного кэша: ключи, использованные в основном кэше повторно
+	tinyLists
+)
+
+const (
+	tinyWindowPercent    = 1  // доля окна в емкости кэша, %
+	tinyProtectedPercent = 80 // доля защищенной части в основном кэше, %
+)
+
+// положение ключа: часть и элемент в ней.
+type tinyNode[K comparable] struct {
+	list int
+	item *dlist.Item[K]
+}
+
+// tinyLFUPolicy - W-TinyLFU (Gil Einziger, Roy Friedman, Ben Manes, "TinyLFU: A Highly Efficient Cache
+// Admission Policy"). новые ключи попадают в небольшое окно LRU. ключ, вытесняемый из окна, допускается
+// в основной кэш (SLRU), только если по оценке частоты обращений он популярнее ключа, который
+// пришлось бы вытеснить из основного кэша. частота оценивается приближенно (count-min sketch)
+// и учитывает обращения и к ключам, которых уже нет в кэше.
+type tinyLFUPolicy[K comparable] struct {
+	lists  [tinyLists]*dlist.List[K]
+	nodes  map[K]tinyNode[K]
+	sketch *countMinSketch
+	hasher Hasher[K]
+
+	windowCap, protectedCap, mainCap int
+}
+
+// NewTinyLFUPolicy создает политику W-TinyLFU. hasher == nil - DefaultHasher.
+// W-TinyLFU устойчива к однократному чтению большого количества ключей и к повторяющимся циклам длиннее кэша,
+// дополнительная память на счетчик частот - от 16 до 32 байт на запись
+// (4 строки по 4 однобайтовых счетчика на запись, ширина строки округляется до степени двойки).
+func NewTinyLFUPolicy[K comparable](hasher Hasher[K]) Policy[K] {
+	if hasher == nil {
+		hasher = DefaultHasher[K]
+	}
+
+	p := &tinyLFUPolicy[K]{hasher: hasher, sketch: newCountMinSketch(0)}
+	p.Reset()
+	return p
+}
+
+func (p *tinyLFUPolicy[K]) Add(key K) {
+	p.sketch.increment(p.hasher(key))
+	p.push(tinyWindow, key)
+}
+
+// Access переносит ключ в начало его части. повторно использованный ключ испытательной части
+// переходит в защищенную, лишние ключи защищенной части возвращаются в испытательную.
+func (p *tinyLFUPolicy[K]) Access(key K) {
+	p.sketch.increment(p.hasher(key))
+
+	n, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	if n.list != tinyProbation {
+		p.lists[n.list].MoveToFront(n.item)
+		return
+	}
+
+	p.unlink(key)
+	p.push(tinyProtected, key)
+	for p.lists[tinyProtected].Len() > p.protectedCap {
+		demoted := p.lists[tinyProtected].Back().Value
+		p.unlink(demoted)
+		p.push(tinyProbation, demoted)
+	}
+}
+
+func (p *tinyLFUPolicy[K]) Remove(key K) {
+	if _, ok := p.nodes[key]; ok {
+		p.unlink(key)
+	}
+}
+
+// Evict переносит лишние ключи окна в начало испытательной части. если основной кэш переполнен,
+// последний перенесенный ключ (кандидат) соревнуется с давно использованным ключом испытательной части:
+// вытесняется ключ с меньшей оценкой частоты, при равенстве - кандидат.
+func (p *tinyLFUPolicy[K]) Evict() (K, bool) {
+	for p.lists[tinyWindow].Len() > p.windowCap {
+		key := p.lists[tinyWindow].Back().Value
+		p.unlink(key)
+		p.push(tinyProbation, key)
+	}
+
+	var victim *dlist.Item[K]
+	switch probation := p.lists[tinyProbation]; {
+	case p.mainLen() > p.mainCap && probation.Len() > 1:
+		candidate := probation.Front()
+		victim = probation.Back()
+		if p.estimate(candidate.Value) <= p.estimate(victim.Value) {
+			victim = candidate
+		}
+	case probation.Len() > 0:
+		victim = probation.Back()
+	case p.lists[tinyProtected].Len() > 0:
+		victim = p.lists[tinyProtected].Back()
+	default:
+		victim = p.lists[tinyWindow].Back()
+	}
+
+	if victim == nil {
+		var zero K
+		return zero, false
+	}
+
+	key := victim.Value
+	p.unlink(key)
+	return key, true
+}
+
+// Keys возвращает ключи защищенной части, окна и испытательной части - каждой от недавно использованных
+// к давно использованным.
+func (p *tinyLFUPolicy[K]) Keys() []K {
+	keys := make([]K, 0, len(p.nodes))
+	for _, list := range []int{tinyProtected, tinyWindow, tinyProbation} {
+		keys = appendKeys(keys, p.lists[list])
+	}
+	return keys
+}
+
+// Resize меняет размеры частей. ключи между частями переносятся при следующих Access и Evict.
+func (p *tinyLFUPolicy[K]) Resize(capacity int) {
+	capacity = maxInt(capacity, 0)
+	p.windowCap = maxInt(capacity*tinyWindowPercent/100, 1)
+	p.mainCap = maxInt(capacity-p.windowCap, 0)
+	p.protectedCap = p.mainCap * tinyProtectedPercent / 100
+	p.sketch = newCountMinSketch(capacity)
+}
+
+func (p *tinyLFUPolicy[K]) Reset() {
+	for i := range p.lists {
+		p.lists[i] = dlist.New[K]()
+	}
+	p.nodes = make(map[K]tinyNode[K])
+	p.sketch.reset()
+}
+
+func (p *tinyLFUPolicy[K]) mainLen() int {
+	return p.lists[tinyProbation].Len() + p.lists[tinyProtected].Len()
+}
+
+func (p *tinyLFUPolicy[K]) estimate(key K) uint8 {
+	return p.sketch.estimate(p.hasher(key))
+}
+
+func (p *tinyLFUPolicy[K]) push(list int, key K) {
+	p.nodes[key] = tinyNode[K]{list: list, item: p.lists[list].PushFront(key)}
+}
+
+func (p *tinyLFUPolicy[K]) unlink(key K) {
+	n := p.nodes[key]
+	p.lists[n.list].Remove(n.item)
+	delete(p.nodes, key)
+}